@@ -2,19 +2,31 @@ package main
 
 import (
 	"context"
+	"expvar"
 	"flag"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/ilyakaznacheev/cleanenv"
+	"github.com/nats-io/nats.go"
 	"golang.org/x/exp/slog"
 
+	"goods-service/internal/good/cache/memory"
 	"goods-service/internal/good/cache/redis"
+	"goods-service/internal/good/controller/http/health"
 	v1 "goods-service/internal/good/controller/http/v1"
+	ln "goods-service/internal/good/log/nats"
 	"goods-service/internal/good/service"
 	"goods-service/internal/good/storage/good/postgres"
+	"goods-service/internal/good/storage/log/clickhouse"
+	"goods-service/internal/good/syncer"
+	pv1 "goods-service/internal/project/controller/http/v1"
+	ps "goods-service/internal/project/service"
+	pp "goods-service/internal/project/storage/postgres"
 
 	cc "goods-service/pkg/clickhouse"
 	hs "goods-service/pkg/http"
@@ -37,14 +49,55 @@ type Config struct {
 		SSLMode  string `env:"POSTGRES_SSL_MODE" env-default:"false"`
 	}
 	Clickhouse struct {
-		Address string `env:"CLICKHOUSE_ADDRESS" env-required:"true"`
+		Address  string `env:"CLICKHOUSE_ADDRESS" env-required:"true"`
+		DB       string `env:"CLICKHOUSE_DB" env-default:"hezzl"`
+		User     string `env:"CLICKHOUSE_USER" env-default:"default"`
+		Password string `env:"CLICKHOUSE_PASSWORD"`
 	}
 	NATS struct {
-		URL string `env:"NATS_URL" env-required:"true"`
+		URL     string `env:"NATS_URL" env-required:"true"`
+		Stream  string `env:"NATS_STREAM" env-default:"GOODS"`
+		Subject string `env:"NATS_SUBJECT" env-default:"goods.logs"`
+	}
+	Outbox struct {
+		BatchSize        int32         `env:"OUTBOX_BATCH_SIZE" env-default:"100"`
+		Interval         time.Duration `env:"OUTBOX_INTERVAL" env-default:"1s"`
+		Retention        time.Duration `env:"OUTBOX_RETENTION" env-default:"168h"`
+		CleanupBatchSize int32         `env:"OUTBOX_CLEANUP_BATCH_SIZE" env-default:"1000"`
+		CleanupInterval  time.Duration `env:"OUTBOX_CLEANUP_INTERVAL" env-default:"1m"`
+		MaxUnsentAge     time.Duration `env:"OUTBOX_MAX_UNSENT_AGE" env-default:"1m"`
+	}
+	Purge struct {
+		Retention time.Duration `env:"PURGE_RETENTION" env-default:"720h"`
+		BatchSize int32         `env:"PURGE_BATCH_SIZE" env-default:"100"`
+		Interval  time.Duration `env:"PURGE_INTERVAL" env-default:"1h"`
+	}
+	Idempotency struct {
+		Window           time.Duration `env:"IDEMPOTENCY_WINDOW" env-default:"24h"`
+		CleanupBatchSize int32         `env:"IDEMPOTENCY_CLEANUP_BATCH_SIZE" env-default:"1000"`
+		CleanupInterval  time.Duration `env:"IDEMPOTENCY_CLEANUP_INTERVAL" env-default:"10m"`
+	}
+	Import struct {
+		ChunkSize int `env:"IMPORT_CHUNK_SIZE" env-default:"1000"`
 	}
 	Redis struct {
 		URL string `env:"REDIS_URL" env-required:"true"`
 	}
+	Cache struct {
+		TTL       time.Duration `env:"CACHE_TTL" env-default:"1m"`
+		KeyPrefix string        `env:"CACHE_KEY_PREFIX"`
+		Codec     string        `env:"CACHE_CODEC" env-default:"json"`
+		LocalSize int           `env:"CACHE_LOCAL_SIZE" env-default:"10000"`
+		LocalTTL  time.Duration `env:"CACHE_LOCAL_TTL" env-default:"5s"`
+	}
+	Invalidator struct {
+		Durable     string        `env:"INVALIDATOR_DURABLE" env-default:"cache-invalidator"`
+		AckWait     time.Duration `env:"INVALIDATOR_ACK_WAIT" env-default:"30s"`
+		BatchSize   int32         `env:"INVALIDATOR_BATCH_SIZE" env-default:"100"`
+		MaxWait     time.Duration `env:"INVALIDATOR_MAX_WAIT" env-default:"100ms"`
+		BackoffBase time.Duration `env:"INVALIDATOR_BACKOFF_BASE" env-default:"200ms"`
+		BackoffMax  time.Duration `env:"INVALIDATOR_BACKOFF_MAX" env-default:"10s"`
+	}
 	HTTP struct{}
 }
 
@@ -76,6 +129,24 @@ func main() {
 		os.Exit(1)
 	}
 	defer natsConn.Drain()
+	js, err := nc.NewJetStream(natsConn, cfg.NATS.Stream, cfg.NATS.Subject)
+	if err != nil {
+		log.Error("failed to initialize jetstream", ls.Error(err))
+		os.Exit(1)
+	}
+	// The invalidator consumer is shared by all replicas: each event is
+	// evicted once and the other replicas learn about it from the
+	// broadcaster. Undecodable events are dead-lettered by the log service,
+	// so the invalidator only skips them.
+	subscription, err := js.PullSubscribe(cfg.NATS.Subject, cfg.Invalidator.Durable,
+		nats.BindStream(cfg.NATS.Stream),
+		nats.AckExplicit(),
+		nats.AckWait(cfg.Invalidator.AckWait),
+	)
+	if err != nil {
+		log.Error("failed to subscribe", ls.Error(err))
+		os.Exit(1)
+	}
 	pool, err := pc.NewConnPool(&pc.Config{
 		Host:     cfg.Postgres.Host,
 		Port:     cfg.Postgres.Port,
@@ -89,23 +160,104 @@ func main() {
 		return
 	}
 	defer pool.Close()
-	clickhouseConn, err := cc.NewConnection(cfg.Clickhouse.Address)
+	clickhouseConn, err := cc.NewConnection(&cc.Config{
+		Address:  cfg.Clickhouse.Address,
+		DB:       cfg.Clickhouse.DB,
+		User:     cfg.Clickhouse.User,
+		Password: cfg.Clickhouse.Password,
+	})
 	if err != nil {
 		log.Error("failed to establish clickhouse connection", ls.Error(err))
 		os.Exit(1)
 	}
 	defer clickhouseConn.Close()
-	cache := redis.NewCache(redisClient)
-	storage := postgres.NewGoodStorage(pool)
-	service := service.NewGoodService(cache, storage)
+	codec, err := redis.NewCodec(cfg.Cache.Codec)
+	if err != nil {
+		log.Error("failed to create cache codec", ls.Error(err))
+		os.Exit(1)
+	}
+	redisCache := redis.NewCache(redisClient, redis.Config{
+		Prefix: cfg.Cache.KeyPrefix,
+		TTL:    cfg.Cache.TTL,
+		Codec:  codec,
+	})
+	broadcaster := redis.NewBroadcaster(redisClient, cfg.Cache.KeyPrefix, log)
+	cache := memory.NewCache(redisCache, broadcaster, cfg.Cache.LocalSize, cfg.Cache.LocalTTL)
+	storage := postgres.NewGoodStorage(pool, cfg.Idempotency.Window, cfg.Import.ChunkSize)
+	logStorage := clickhouse.NewLogStorage(clickhouseConn)
+	service := service.NewGoodService(cache, storage, logStorage, log)
 	controller := v1.NewController(service)
+	projectStorage := pp.NewProjectStorage(pool)
+	projectService := ps.NewProjectService(projectStorage)
+	projectController := pv1.NewController(projectService)
 	mux := chi.NewRouter()
 	controller.Register(mux)
+	projectController.Register(mux)
+	cleaner, err := syncer.NewOutboxCleaner(pool, syncer.OutboxCleanerConfig{
+		Retention: cfg.Outbox.Retention,
+		BatchSize: cfg.Outbox.CleanupBatchSize,
+		Interval:  cfg.Outbox.CleanupInterval,
+	}, log)
+	if err != nil {
+		log.Error("failed to initialize outbox cleaner", ls.Error(err))
+		os.Exit(1)
+	}
+	purger, err := syncer.NewGoodsPurger(storage, syncer.GoodsPurgerConfig{
+		Retention: cfg.Purge.Retention,
+		BatchSize: cfg.Purge.BatchSize,
+		Interval:  cfg.Purge.Interval,
+	}, log)
+	if err != nil {
+		log.Error("failed to initialize goods purger", ls.Error(err))
+		os.Exit(1)
+	}
+	keysCleaner, err := syncer.NewKeysCleaner(storage, syncer.KeysCleanerConfig{
+		BatchSize: cfg.Idempotency.CleanupBatchSize,
+		Interval:  cfg.Idempotency.CleanupInterval,
+	}, log)
+	if err != nil {
+		log.Error("failed to initialize idempotency keys cleaner", ls.Error(err))
+		os.Exit(1)
+	}
+	health.NewController(cleaner, cfg.Outbox.MaxUnsentAge, cfg.Outbox.CleanupInterval).Register(mux)
+	mux.Handle("/debug/vars", expvar.Handler())
 	server := hs.NewServer(mux)
+	pusher := syncer.NewLogPusher(pool, ln.NewLogWriter(js, cfg.NATS.Subject),
+		cfg.Outbox.BatchSize, cfg.Outbox.Interval, log)
+	invalidator := syncer.NewCacheInvalidator(
+		ln.NewLogReader(js, subscription, cfg.Invalidator.BatchSize, cfg.Invalidator.MaxWait, ""),
+		cache, cfg.Invalidator.BackoffBase, cfg.Invalidator.BackoffMax, log)
 
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
+	var wg sync.WaitGroup
+	wg.Add(6)
+	go func() {
+		defer wg.Done()
+		pusher.PushLogs(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		broadcaster.Listen(ctx, cache.Invalidate)
+	}()
+	go func() {
+		defer wg.Done()
+		invalidator.InvalidateCache(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		cleaner.CleanOutbox(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		purger.PurgeGoods(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		keysCleaner.CleanKeys(ctx)
+	}()
+
 	server.Run()
 
 	<-ctx.Done()
@@ -114,4 +266,5 @@ func main() {
 	if err != nil {
 		log.Error("failed to shutdown server", ls.Error(err))
 	}
+	wg.Wait()
 }
@@ -1,3 +1,126 @@
 package main
 
-func main() {}
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ilyakaznacheev/cleanenv"
+	"github.com/nats-io/nats.go"
+	"golang.org/x/exp/slog"
+
+	ln "goods-service/internal/good/log/nats"
+	"goods-service/internal/good/storage/log/clickhouse"
+	"goods-service/internal/good/syncer"
+
+	cc "goods-service/pkg/clickhouse"
+	ls "goods-service/pkg/log/slog"
+	nc "goods-service/pkg/nats"
+)
+
+type Config struct {
+	Log struct {
+		Level string `env:"LOG_LEVEL" env-default:"debug"`
+	}
+	Clickhouse struct {
+		Address  string `env:"CLICKHOUSE_ADDRESS" env-required:"true"`
+		DB       string `env:"CLICKHOUSE_DB" env-default:"hezzl"`
+		User     string `env:"CLICKHOUSE_USER" env-default:"default"`
+		Password string `env:"CLICKHOUSE_PASSWORD"`
+	}
+	NATS struct {
+		URL        string        `env:"NATS_URL" env-required:"true"`
+		Stream     string        `env:"NATS_STREAM" env-default:"GOODS"`
+		Subject    string        `env:"NATS_SUBJECT" env-default:"goods.logs"`
+		Durable    string        `env:"NATS_DURABLE" env-default:"log-syncer"`
+		AckWait    time.Duration `env:"NATS_ACK_WAIT" env-default:"1m"`
+		DLQStream  string        `env:"NATS_DLQ_STREAM" env-default:"GOODS_DLQ"`
+		DLQSubject string        `env:"NATS_DLQ_SUBJECT" env-default:"goods.logs.dlq"`
+	}
+	Sync struct {
+		BatchSize    int32         `env:"SYNC_BATCH_SIZE" env-default:"1000"`
+		MaxWait      time.Duration `env:"SYNC_MAX_WAIT" env-default:"1s"`
+		FlushTimeout time.Duration `env:"SYNC_FLUSH_TIMEOUT" env-default:"10s"`
+		MaxAttempts  int           `env:"SYNC_MAX_ATTEMPTS" env-default:"5"`
+		BackoffBase  time.Duration `env:"SYNC_BACKOFF_BASE" env-default:"200ms"`
+		BackoffMax   time.Duration `env:"SYNC_BACKOFF_MAX" env-default:"10s"`
+	}
+}
+
+func main() {
+	var (
+		cfg Config
+		log *slog.Logger
+		err error
+	)
+	flag.Parse()
+	log = ls.NewLogger(cfg.Log.Level)
+	log.Info("starting log service...")
+	log.Info("reading config...")
+	err = cleanenv.ReadEnv(&cfg)
+	if err != nil {
+		log.Error("failed to read env", ls.Error(err))
+		os.Exit(1)
+	}
+	log.Info("initializing clients...")
+	natsConn, err := nc.NewConnection(cfg.NATS.URL)
+	if err != nil {
+		log.Error("failed to establish nats connection", ls.Error(err))
+		os.Exit(1)
+	}
+	defer natsConn.Drain()
+	js, err := nc.NewJetStream(natsConn, cfg.NATS.Stream, cfg.NATS.Subject)
+	if err != nil {
+		log.Error("failed to initialize jetstream", ls.Error(err))
+		os.Exit(1)
+	}
+	_, err = nc.NewJetStream(natsConn, cfg.NATS.DLQStream, cfg.NATS.DLQSubject)
+	if err != nil {
+		log.Error("failed to initialize dead letter stream", ls.Error(err))
+		os.Exit(1)
+	}
+	subscription, err := js.PullSubscribe(cfg.NATS.Subject, cfg.NATS.Durable,
+		nats.BindStream(cfg.NATS.Stream),
+		nats.AckExplicit(),
+		nats.AckWait(cfg.NATS.AckWait),
+	)
+	if err != nil {
+		log.Error("failed to subscribe", ls.Error(err))
+		os.Exit(1)
+	}
+	clickhouseConn, err := cc.NewConnection(&cc.Config{
+		Address:  cfg.Clickhouse.Address,
+		DB:       cfg.Clickhouse.DB,
+		User:     cfg.Clickhouse.User,
+		Password: cfg.Clickhouse.Password,
+	})
+	if err != nil {
+		log.Error("failed to establish clickhouse connection", ls.Error(err))
+		os.Exit(1)
+	}
+	defer clickhouseConn.Close()
+	reader := ln.NewLogReader(js, subscription, cfg.Sync.BatchSize, cfg.Sync.MaxWait, cfg.NATS.DLQSubject)
+	storage := clickhouse.NewLogStorage(clickhouseConn)
+	logSyncer := syncer.NewLogSyncer(reader, storage, syncer.LogSyncerConfig{
+		FlushTimeout: cfg.Sync.FlushTimeout,
+		MaxAttempts:  cfg.Sync.MaxAttempts,
+		BackoffBase:  cfg.Sync.BackoffBase,
+		BackoffMax:   cfg.Sync.BackoffMax,
+	}, log)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		logSyncer.SyncLogs(ctx)
+	}()
+
+	<-ctx.Done()
+	log.Info("shutting down, flushing in-flight batch...")
+	<-done
+}
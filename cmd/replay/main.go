@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ilyakaznacheev/cleanenv"
+	"github.com/nats-io/nats.go"
+	"golang.org/x/exp/slog"
+
+	ln "goods-service/internal/good/log/nats"
+
+	ls "goods-service/pkg/log/slog"
+	nc "goods-service/pkg/nats"
+)
+
+type Config struct {
+	Log struct {
+		Level string `env:"LOG_LEVEL" env-default:"debug"`
+	}
+	NATS struct {
+		URL        string `env:"NATS_URL" env-required:"true"`
+		Subject    string `env:"NATS_SUBJECT" env-default:"goods.logs"`
+		DLQStream  string `env:"NATS_DLQ_STREAM" env-default:"GOODS_DLQ"`
+		DLQSubject string `env:"NATS_DLQ_SUBJECT" env-default:"goods.logs.dlq"`
+		DLQDurable string `env:"NATS_DLQ_DURABLE" env-default:"dlq-replayer"`
+	}
+	Replay struct {
+		BatchSize int32 `env:"REPLAY_BATCH_SIZE" env-default:"100"`
+	}
+}
+
+// replay moves the messages parked on the dead-letter subject back to the
+// main log subject, so they are ingested again once the cause is fixed.
+func main() {
+	var (
+		cfg Config
+		log *slog.Logger
+		err error
+	)
+	flag.Parse()
+	log = ls.NewLogger(cfg.Log.Level)
+	log.Info("reading config...")
+	err = cleanenv.ReadEnv(&cfg)
+	if err != nil {
+		log.Error("failed to read env", ls.Error(err))
+		os.Exit(1)
+	}
+	natsConn, err := nc.NewConnection(cfg.NATS.URL)
+	if err != nil {
+		log.Error("failed to establish nats connection", ls.Error(err))
+		os.Exit(1)
+	}
+	defer natsConn.Drain()
+	js, err := nc.NewJetStream(natsConn, cfg.NATS.DLQStream, cfg.NATS.DLQSubject)
+	if err != nil {
+		log.Error("failed to initialize dead letter stream", ls.Error(err))
+		os.Exit(1)
+	}
+	subscription, err := js.PullSubscribe(cfg.NATS.DLQSubject, cfg.NATS.DLQDurable,
+		nats.BindStream(cfg.NATS.DLQStream),
+		nats.AckExplicit(),
+	)
+	if err != nil {
+		log.Error("failed to subscribe", ls.Error(err))
+		os.Exit(1)
+	}
+	replayer := ln.NewDeadLetterReplayer(js, subscription, cfg.Replay.BatchSize, cfg.NATS.Subject)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	replayed, err := replayer.Replay(ctx)
+	log.Info("replayed dead letters", slog.Int("count", replayed))
+	if err != nil {
+		log.Error("failed to replay dead letters", ls.Error(err))
+		os.Exit(1)
+	}
+}
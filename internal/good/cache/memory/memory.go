@@ -0,0 +1,222 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"sync"
+	"time"
+
+	"goods-service/internal/good/domain"
+)
+
+// stats counts hits and misses of both tiers, it is served by expvar at
+// /debug/vars under the goods_cache key.
+var stats = expvar.NewMap("goods_cache")
+
+type RemoteCache interface {
+	SetGoodsList(ctx context.Context, listGoods domain.ListGoods, version domain.CacheVersion,
+		goodsList domain.GoodsList) (err error)
+	GetGoodsList(ctx context.Context, listGoods domain.ListGoods) (goodsList domain.GoodsList,
+		version domain.CacheVersion, err error)
+	DeleteGoodsList(ctx context.Context, projectID int64) (err error)
+	SetGood(ctx context.Context, good domain.Good, version domain.CacheVersion) (err error)
+	GetGood(ctx context.Context, getGood domain.GetGood) (good domain.Good, version domain.CacheVersion, err error)
+	DeleteGoods(ctx context.Context, projectID int64, ids ...int64) (err error)
+}
+
+type Broadcaster interface {
+	PublishInvalidation(ctx context.Context, invalidation domain.CacheInvalidation) (err error)
+}
+
+// Cache is an in-process LRU in front of the remote cache. Sets and
+// deletes go through to the remote cache. Only deletes are broadcast, so
+// that other replicas drop the entries from their local tier via
+// Invalidate; a set only fills the cache with what the storage holds.
+// Lists are keyed by a local generation per project; invalidating a
+// project bumps it instead of scanning the LRU. Goods have a generation
+// per project too, so that a good read before an invalidation is not
+// cached after it.
+type Cache struct {
+	remote      RemoteCache
+	broadcaster Broadcaster
+	lists       *lru
+	goods       *lru
+
+	mu              sync.Mutex
+	generations     map[int64]uint64
+	goodGenerations map[int64]uint64
+}
+
+func NewCache(remote RemoteCache, broadcaster Broadcaster, capacity int, ttl time.Duration) (cache *Cache) {
+	cache = &Cache{
+		remote:      remote,
+		broadcaster: broadcaster,
+		lists:       newLRU(capacity, ttl),
+		goods:       newLRU(capacity, ttl),
+		generations: make(map[int64]uint64),
+
+		goodGenerations: make(map[int64]uint64),
+	}
+	return
+}
+
+func (c *Cache) SetGoodsList(ctx context.Context, listGoods domain.ListGoods, version domain.CacheVersion,
+	goodsList domain.GoodsList) (err error) {
+	c.mu.Lock()
+	if c.generations[listGoods.ProjectID] == version.Local {
+		c.lists.set(listKey(version.Local, listGoods), goodsList)
+	}
+	c.mu.Unlock()
+	err = c.remote.SetGoodsList(ctx, listGoods, version, goodsList)
+	if err != nil {
+		err = fmt.Errorf("remote set goods list: %w", err)
+		return
+	}
+	return
+}
+
+func (c *Cache) GetGoodsList(ctx context.Context, listGoods domain.ListGoods) (goodsList domain.GoodsList,
+	version domain.CacheVersion, err error) {
+	c.mu.Lock()
+	generation := c.generations[listGoods.ProjectID]
+	c.mu.Unlock()
+	key := listKey(generation, listGoods)
+	if value, ok := c.lists.get(key); ok {
+		stats.Add("memory_hits", 1)
+		goodsList = value.(domain.GoodsList)
+		return
+	}
+	stats.Add("memory_misses", 1)
+	goodsList, version, err = c.remote.GetGoodsList(ctx, listGoods)
+	version.Local = generation
+	if err != nil {
+		countRemote(err)
+		err = fmt.Errorf("remote get goods list: %w", err)
+		return
+	}
+	stats.Add("redis_hits", 1)
+	c.lists.set(key, goodsList)
+	return
+}
+
+func (c *Cache) DeleteGoodsList(ctx context.Context, projectID int64) (err error) {
+	invalidation := domain.CacheInvalidation{
+		ProjectID: projectID,
+		Lists:     true,
+	}
+	c.Invalidate(invalidation)
+	err = c.remote.DeleteGoodsList(ctx, projectID)
+	if err != nil {
+		err = fmt.Errorf("remote delete goods list: %w", err)
+		return
+	}
+	err = c.broadcast(ctx, invalidation)
+	return
+}
+
+func (c *Cache) SetGood(ctx context.Context, good domain.Good, version domain.CacheVersion) (err error) {
+	c.mu.Lock()
+	if c.goodGenerations[good.ProjectID] == version.Local {
+		c.goods.set(goodKey(good.ProjectID, good.ID), good)
+	}
+	c.mu.Unlock()
+	err = c.remote.SetGood(ctx, good, version)
+	if err != nil {
+		err = fmt.Errorf("remote set good: %w", err)
+		return
+	}
+	return
+}
+
+func (c *Cache) GetGood(ctx context.Context, getGood domain.GetGood) (good domain.Good, version domain.CacheVersion,
+	err error) {
+	c.mu.Lock()
+	generation := c.goodGenerations[getGood.ProjectID]
+	c.mu.Unlock()
+	key := goodKey(getGood.ProjectID, getGood.ID)
+	if value, ok := c.goods.get(key); ok {
+		stats.Add("memory_hits", 1)
+		good = value.(domain.Good)
+		return
+	}
+	stats.Add("memory_misses", 1)
+	good, version, err = c.remote.GetGood(ctx, getGood)
+	version.Local = generation
+	if err != nil {
+		countRemote(err)
+		err = fmt.Errorf("remote get good: %w", err)
+		return
+	}
+	stats.Add("redis_hits", 1)
+	c.mu.Lock()
+	if c.goodGenerations[getGood.ProjectID] == generation {
+		c.goods.set(key, good)
+	}
+	c.mu.Unlock()
+	return
+}
+
+func (c *Cache) DeleteGoods(ctx context.Context, projectID int64, ids ...int64) (err error) {
+	if len(ids) == 0 {
+		return
+	}
+	invalidation := domain.CacheInvalidation{
+		ProjectID: projectID,
+		IDs:       ids,
+	}
+	c.Invalidate(invalidation)
+	err = c.remote.DeleteGoods(ctx, projectID, ids...)
+	if err != nil {
+		err = fmt.Errorf("remote delete goods: %w", err)
+		return
+	}
+	err = c.broadcast(ctx, invalidation)
+	return
+}
+
+// Invalidate drops the described entries from the local tier only. It is
+// called for local writes and for invalidations received from other
+// replicas.
+func (c *Cache) Invalidate(invalidation domain.CacheInvalidation) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(invalidation.IDs) > 0 {
+		c.goodGenerations[invalidation.ProjectID]++
+	}
+	for _, id := range invalidation.IDs {
+		c.goods.delete(goodKey(invalidation.ProjectID, id))
+	}
+	if invalidation.Lists {
+		c.generations[invalidation.ProjectID]++
+		c.generations[0]++
+	}
+}
+
+func (c *Cache) broadcast(ctx context.Context, invalidation domain.CacheInvalidation) (err error) {
+	err = c.broadcaster.PublishInvalidation(ctx, invalidation)
+	if err != nil {
+		err = fmt.Errorf("publish invalidation: %w", err)
+		return
+	}
+	return
+}
+
+func listKey(generation uint64, listGoods domain.ListGoods) (key string) {
+	key = fmt.Sprintf("%d:%s", generation, listGoods.CacheKey())
+	return
+}
+
+func goodKey(projectID, id int64) (key string) {
+	key = fmt.Sprintf("%d:%d", projectID, id)
+	return
+}
+
+func countRemote(err error) {
+	if errors.Is(err, domain.ErrCacheMiss) {
+		stats.Add("redis_misses", 1)
+		return
+	}
+	stats.Add("redis_errors", 1)
+}
@@ -0,0 +1,95 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/exp/slog"
+
+	"goods-service/internal/good/domain"
+
+	ls "goods-service/pkg/log/slog"
+)
+
+const invalidationChannel = "goodsInvalidations"
+
+type invalidation struct {
+	Origin    string  `json:"origin"`
+	ProjectID int64   `json:"projectId"`
+	IDs       []int64 `json:"ids"`
+	Lists     bool    `json:"lists"`
+}
+
+// Broadcaster spreads cache invalidations between replicas over Redis
+// pub/sub. Messages published by this replica are skipped on receipt.
+type Broadcaster struct {
+	client  *redis.Client
+	channel string
+	origin  string
+	log     *slog.Logger
+}
+
+func NewBroadcaster(client *redis.Client, prefix string, log *slog.Logger) (broadcaster *Broadcaster) {
+	broadcaster = &Broadcaster{
+		client:  client,
+		channel: prefix + invalidationChannel,
+		origin:  uuid.NewString(),
+		log:     log,
+	}
+	return
+}
+
+func (b *Broadcaster) PublishInvalidation(ctx context.Context, cacheInvalidation domain.CacheInvalidation) (
+	err error) {
+	jsonData, err := json.Marshal(invalidation{
+		Origin:    b.origin,
+		ProjectID: cacheInvalidation.ProjectID,
+		IDs:       cacheInvalidation.IDs,
+		Lists:     cacheInvalidation.Lists,
+	})
+	if err != nil {
+		err = fmt.Errorf("json marshal: %w", err)
+		return
+	}
+	err = b.client.Publish(ctx, b.channel, jsonData).Err()
+	if err != nil {
+		err = fmt.Errorf("publish: %w", err)
+		return
+	}
+	return
+}
+
+// Listen passes invalidations published by other replicas to handle until
+// ctx is cancelled.
+func (b *Broadcaster) Listen(ctx context.Context, handle func(cacheInvalidation domain.CacheInvalidation)) {
+	pubsub := b.client.Subscribe(ctx, b.channel)
+	defer pubsub.Close()
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+			var inv invalidation
+			err := json.Unmarshal([]byte(message.Payload), &inv)
+			if err != nil {
+				b.log.Error("failed to decode cache invalidation", ls.Error(err))
+				continue
+			}
+			if inv.Origin == b.origin {
+				continue
+			}
+			handle(domain.CacheInvalidation{
+				ProjectID: inv.ProjectID,
+				IDs:       inv.IDs,
+				Lists:     inv.Lists,
+			})
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package redis
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// schemaVersion must be bumped whenever the cached model changes in a way
+// older bytes would be decoded wrongly; entries of another version are
+// treated as a miss.
+const schemaVersion byte = 2
+
+const headerSize = 2
+
+var errStaleEntry = errors.New("stale cache entry")
+
+type Codec interface {
+	ID() byte
+	Marshal(v any) (data []byte, err error)
+	Unmarshal(data []byte, v any) (err error)
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) ID() byte {
+	return 1
+}
+
+func (jsonCodec) Marshal(v any) (data []byte, err error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) (err error) {
+	return json.Unmarshal(data, v)
+}
+
+// msgpackCodec reuses the json struct tags of the model.
+type msgpackCodec struct{}
+
+func (msgpackCodec) ID() byte {
+	return 2
+}
+
+func (msgpackCodec) Marshal(v any) (data []byte, err error) {
+	var buf bytes.Buffer
+	encoder := msgpack.NewEncoder(&buf)
+	encoder.SetCustomStructTag("json")
+	encoder.UseCompactInts(true)
+	err = encoder.Encode(v)
+	if err != nil {
+		return
+	}
+	data = buf.Bytes()
+	return
+}
+
+func (msgpackCodec) Unmarshal(data []byte, v any) (err error) {
+	decoder := msgpack.NewDecoder(bytes.NewReader(data))
+	decoder.SetCustomStructTag("json")
+	return decoder.Decode(v)
+}
+
+func NewCodec(name string) (codec Codec, err error) {
+	switch name {
+	case "json":
+		codec = jsonCodec{}
+	case "msgpack":
+		codec = msgpackCodec{}
+	default:
+		err = fmt.Errorf("unknown codec: %s", name)
+	}
+	return
+}
+
+// encode prefixes the payload with the schema version and the codec id.
+func encode(codec Codec, v any) (data []byte, err error) {
+	payload, err := codec.Marshal(v)
+	if err != nil {
+		err = fmt.Errorf("marshal: %w", err)
+		return
+	}
+	data = make([]byte, 0, headerSize+len(payload))
+	data = append(data, schemaVersion, codec.ID())
+	data = append(data, payload...)
+	return
+}
+
+// decode returns errStaleEntry for entries written with another schema
+// version or codec, e.g. by a replica running a previous release.
+func decode(codec Codec, data []byte, v any) (err error) {
+	if len(data) < headerSize || data[0] != schemaVersion || data[1] != codec.ID() {
+		err = errStaleEntry
+		return
+	}
+	err = codec.Unmarshal(data[headerSize:], v)
+	if err != nil {
+		err = fmt.Errorf("unmarshal: %w", err)
+		return
+	}
+	return
+}
@@ -4,10 +4,11 @@ import "time"
 
 type (
 	meta struct {
-		Total   int32 `json:"total"`
-		Removed int32 `json:"removed"`
-		Limit   int32 `json:"limit"`
-		Offset  int32 `json:"offset"`
+		Total      int32  `json:"total"`
+		Removed    int32  `json:"removed"`
+		Limit      int32  `json:"limit"`
+		Offset     int32  `json:"offset"`
+		NextCursor string `json:"nextCursor"`
 	}
 
 	good struct {
@@ -18,6 +19,7 @@ type (
 		Priority    int32     `json:"priority"`
 		Removed     bool      `json:"removed"`
 		CreatedAt   time.Time `json:"created_at"`
+		Version     int64     `json:"version"`
 	}
 
 	listOfGoods struct {
@@ -2,8 +2,11 @@ package redis
 
 import (
 	"context"
-	"encoding/json"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -12,45 +15,173 @@ import (
 )
 
 const (
-	goodsListKey = "goodsList"
-	ttl          = time.Minute
+	goodPrefix      = "good"
+	goodsListPrefix = "goodsList"
+	allProjects     = "all"
 )
 
+type Config struct {
+	// Prefix is prepended to every key, so that several tenants can share
+	// one Redis.
+	Prefix string
+	TTL    time.Duration
+	Codec  Codec
+}
+
+// Cache stores goods lists under a key derived from the whole list query.
+// Keys live in a namespace per project whose version is bumped on every
+// write to that project, which drops all of its entries at once without
+// touching other projects. Queries that span all projects share their own
+// namespace, bumped on every write. Goods are stored one per key, next to
+// a per-project counter of their evictions. Both versions are read on a
+// miss and checked when the value read from the storage is set, so that
+// an eviction in between is not undone.
 type Cache struct {
 	client *redis.Client
+	config Config
 }
 
-func (c *Cache) SetGoodsList(ctx context.Context, goodsList domain.GoodsList) (err error) {
-	var jsonData []byte
+// SetGoodsList stores the list in the namespace version the miss was seen
+// at. If the namespace was bumped since, the entry is never read.
+func (c *Cache) SetGoodsList(ctx context.Context, listGoods domain.ListGoods, version domain.CacheVersion,
+	goodsList domain.GoodsList) (err error) {
 	list := toRedis(goodsList)
-	jsonData, err = json.Marshal(&list)
+	data, err := encode(c.config.Codec, &list)
+	if err != nil {
+		err = fmt.Errorf("encode: %w", err)
+		return
+	}
+	err = c.client.Set(ctx, c.listKey(listGoods, version.Remote), data, c.config.TTL).Err()
 	if err != nil {
-		err = fmt.Errorf("json marshal: %w", err)
+		err = fmt.Errorf("set: %w", err)
 		return
 	}
-	err = c.client.Set(ctx, goodsListKey, jsonData, ttl).Err()
 	return
 }
 
-func (c *Cache) GetGoodsList(ctx context.Context) (goodsList domain.GoodsList, err error) {
-	var jsonData []byte
-	jsonData, err = c.client.Get(ctx, goodsListKey).Bytes()
+func (c *Cache) GetGoodsList(ctx context.Context, listGoods domain.ListGoods) (goodsList domain.GoodsList,
+	version domain.CacheVersion, err error) {
+	version.Remote, err = getVersion(ctx, c.client, c.versionKey(namespace(listGoods.ProjectID)))
 	if err != nil {
+		err = fmt.Errorf("get list version: %w", err)
+		return
+	}
+	var data []byte
+	data, err = c.client.Get(ctx, c.listKey(listGoods, version.Remote)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			err = domain.ErrCacheMiss
+		}
 		err = fmt.Errorf("get: %w", err)
 		return
 	}
 	var list listOfGoods
-	err = json.Unmarshal(jsonData, &list)
+	err = decode(c.config.Codec, data, &list)
 	if err != nil {
-		err = fmt.Errorf("json unmarshal: %w", err)
+		if errors.Is(err, errStaleEntry) {
+			err = domain.ErrCacheMiss
+		}
+		err = fmt.Errorf("decode: %w", err)
 		return
 	}
 	goodsList = fromRedis(list)
 	return
 }
 
-func (c *Cache) DeleteGoodsList(ctx context.Context) (err error) {
-	err = c.client.Del(ctx, goodsListKey).Err()
+// DeleteGoodsList invalidates every cached list of the project, including
+// the lists that span all projects.
+func (c *Cache) DeleteGoodsList(ctx context.Context, projectID int64) (err error) {
+	pipe := c.client.TxPipeline()
+	pipe.Incr(ctx, c.versionKey(namespace(projectID)))
+	pipe.Incr(ctx, c.versionKey(allProjects))
+	_, err = pipe.Exec(ctx)
+	if err != nil {
+		err = fmt.Errorf("incr versions: %w", err)
+		return
+	}
+	return
+}
+
+// SetGood stores the good unless goods of its project were evicted since
+// the miss was seen.
+func (c *Cache) SetGood(ctx context.Context, good domain.Good, version domain.CacheVersion) (err error) {
+	item := toRedisGood(good)
+	data, err := encode(c.config.Codec, &item)
+	if err != nil {
+		err = fmt.Errorf("encode: %w", err)
+		return
+	}
+	versionKey := c.goodsVersionKey(good.ProjectID)
+	err = c.client.Watch(ctx, func(tx *redis.Tx) (err error) {
+		current, err := getVersion(ctx, tx, versionKey)
+		if err != nil {
+			err = fmt.Errorf("get goods version: %w", err)
+			return
+		}
+		if current != version.Remote {
+			return
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) (err error) {
+			pipe.Set(ctx, c.goodKey(good.ProjectID, good.ID), data, c.config.TTL)
+			return
+		})
+		return
+	}, versionKey)
+	if err != nil {
+		if errors.Is(err, redis.TxFailedErr) {
+			err = nil
+			return
+		}
+		err = fmt.Errorf("set: %w", err)
+		return
+	}
+	return
+}
+
+func (c *Cache) GetGood(ctx context.Context, getGood domain.GetGood) (item domain.Good, version domain.CacheVersion,
+	err error) {
+	values, err := c.client.MGet(ctx, c.goodsVersionKey(getGood.ProjectID),
+		c.goodKey(getGood.ProjectID, getGood.ID)).Result()
+	if err != nil {
+		err = fmt.Errorf("mget: %w", err)
+		return
+	}
+	if values[0] != nil {
+		version.Remote, err = strconv.ParseInt(values[0].(string), 10, 64)
+		if err != nil {
+			err = fmt.Errorf("parse goods version: %w", err)
+			return
+		}
+	}
+	if values[1] == nil {
+		err = fmt.Errorf("get: %w", domain.ErrCacheMiss)
+		return
+	}
+	var g good
+	err = decode(c.config.Codec, []byte(values[1].(string)), &g)
+	if err != nil {
+		if errors.Is(err, errStaleEntry) {
+			err = domain.ErrCacheMiss
+		}
+		err = fmt.Errorf("decode: %w", err)
+		return
+	}
+	item = fromRedisGood(g)
+	return
+}
+
+func (c *Cache) DeleteGoods(ctx context.Context, projectID int64, ids ...int64) (err error) {
+	if len(ids) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, c.goodKey(projectID, id))
+	}
+	pipe := c.client.TxPipeline()
+	pipe.Del(ctx, keys...)
+	pipe.Incr(ctx, c.goodsVersionKey(projectID))
+	_, err = pipe.Exec(ctx)
 	if err != nil {
 		err = fmt.Errorf("del: %w", err)
 		return
@@ -58,9 +189,60 @@ func (c *Cache) DeleteGoodsList(ctx context.Context) (err error) {
 	return
 }
 
-func NewCache(client *redis.Client) (cache *Cache) {
+// getVersion reads a version counter, a missing one is zero.
+func getVersion(ctx context.Context, client redis.Cmdable, key string) (version int64, err error) {
+	version, err = client.Get(ctx, key).Int64()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			err = nil
+			return
+		}
+		err = fmt.Errorf("get: %w", err)
+		return
+	}
+	return
+}
+
+func (c *Cache) listKey(listGoods domain.ListGoods, version int64) (key string) {
+	key = fmt.Sprintf("%s%s:%s:v%d:%s", c.config.Prefix, goodsListPrefix, namespace(listGoods.ProjectID), version,
+		queryHash(listGoods))
+	return
+}
+
+func (c *Cache) goodKey(projectID, id int64) (key string) {
+	key = fmt.Sprintf("%s%s:%d:%d", c.config.Prefix, goodPrefix, projectID, id)
+	return
+}
+
+func (c *Cache) goodsVersionKey(projectID int64) (key string) {
+	key = fmt.Sprintf("%s%s:%d:version", c.config.Prefix, goodPrefix, projectID)
+	return
+}
+
+func namespace(projectID int64) (ns string) {
+	if projectID == 0 {
+		ns = allProjects
+		return
+	}
+	ns = strconv.FormatInt(projectID, 10)
+	return
+}
+
+func (c *Cache) versionKey(ns string) (key string) {
+	key = fmt.Sprintf("%s%s:%s:version", c.config.Prefix, goodsListPrefix, ns)
+	return
+}
+
+func queryHash(listGoods domain.ListGoods) (hash string) {
+	sum := sha1.Sum([]byte(listGoods.CacheKey()))
+	hash = hex.EncodeToString(sum[:])
+	return
+}
+
+func NewCache(client *redis.Client, config Config) (cache *Cache) {
 	cache = &Cache{
 		client: client,
+		config: config,
 	}
 	return
 }
@@ -68,21 +250,15 @@ func NewCache(client *redis.Client) (cache *Cache) {
 func toRedis(goodsList domain.GoodsList) (list listOfGoods) {
 	goods := make([]good, 0, len(goodsList.Goods))
 	for _, item := range goodsList.Goods {
-		goods = append(goods, good{
-			ID:          item.ID,
-			ProjectID:   item.ProjectID,
-			Name:        item.Name,
-			Description: item.Description,
-			Priority:    item.Priority,
-			CreatedAt:   item.CreatedAt,
-		})
+		goods = append(goods, toRedisGood(item))
 	}
 	list = listOfGoods{
 		Meta: meta{
-			Total:   goodsList.Meta.Total,
-			Removed: goodsList.Meta.Removed,
-			Limit:   goodsList.Meta.Limit,
-			Offset:  goodsList.Meta.Offset,
+			Total:      goodsList.Meta.Total,
+			Removed:    goodsList.Meta.Removed,
+			Limit:      goodsList.Meta.Limit,
+			Offset:     goodsList.Meta.Offset,
+			NextCursor: goodsList.Meta.NextCursor,
 		},
 		Goods: goods,
 	}
@@ -90,25 +266,47 @@ func toRedis(goodsList domain.GoodsList) (list listOfGoods) {
 }
 
 func fromRedis(list listOfGoods) (goodsList domain.GoodsList) {
-	goods := make([]domain.Good, 0, len(goodsList.Goods))
+	goods := make([]domain.Good, 0, len(list.Goods))
 	for _, item := range list.Goods {
-		goods = append(goods, domain.Good{
-			ID:          item.ID,
-			ProjectID:   item.ProjectID,
-			Name:        item.Name,
-			Description: item.Description,
-			Priority:    item.Priority,
-			CreatedAt:   item.CreatedAt,
-		})
+		goods = append(goods, fromRedisGood(item))
 	}
 	goodsList = domain.GoodsList{
 		Meta: domain.Meta{
-			Total:   list.Meta.Total,
-			Removed: list.Meta.Removed,
-			Limit:   list.Meta.Limit,
-			Offset:  list.Meta.Offset,
+			Total:      list.Meta.Total,
+			Removed:    list.Meta.Removed,
+			Limit:      list.Meta.Limit,
+			Offset:     list.Meta.Offset,
+			NextCursor: list.Meta.NextCursor,
 		},
 		Goods: goods,
 	}
 	return
 }
+
+func toRedisGood(item domain.Good) (g good) {
+	g = good{
+		ID:          item.ID,
+		ProjectID:   item.ProjectID,
+		Name:        item.Name,
+		Description: item.Description,
+		Priority:    item.Priority,
+		Removed:     item.Removed,
+		CreatedAt:   item.CreatedAt,
+		Version:     item.Version,
+	}
+	return
+}
+
+func fromRedisGood(item good) (g domain.Good) {
+	g = domain.Good{
+		ID:          item.ID,
+		ProjectID:   item.ProjectID,
+		Name:        item.Name,
+		Description: item.Description,
+		Priority:    item.Priority,
+		Removed:     item.Removed,
+		CreatedAt:   item.CreatedAt,
+		Version:     item.Version,
+	}
+	return
+}
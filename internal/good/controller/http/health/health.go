@@ -0,0 +1,69 @@
+package health
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+
+	"goods-service/internal/good/domain"
+)
+
+const (
+	statusOK       = "ok"
+	statusDegraded = "degraded"
+)
+
+type OutboxMonitor interface {
+	OutboxStats() (stats domain.OutboxStats)
+}
+
+// Controller reports the service as degraded once the oldest unsent
+// outbox event is older than maxOutboxAge, which means the relay is stuck
+// or falling behind. The stats are refreshed every statsInterval; when
+// they were never collected or a refresh is more than one interval
+// overdue, the state of the outbox is unknown and reported as degraded
+// too.
+type Controller struct {
+	monitor       OutboxMonitor
+	maxOutboxAge  time.Duration
+	statsInterval time.Duration
+}
+
+func (c *Controller) health(w http.ResponseWriter, r *http.Request) {
+	stats := c.monitor.OutboxStats()
+	now := time.Now()
+	var oldestAge time.Duration
+	if !stats.OldestUnsentAt.IsZero() {
+		oldestAge = now.Sub(stats.OldestUnsentAt)
+	}
+	status := statusOK
+	code := http.StatusOK
+	if stats.CheckedAt.IsZero() || now.Sub(stats.CheckedAt) > 2*c.statsInterval || oldestAge > c.maxOutboxAge {
+		status = statusDegraded
+		code = http.StatusServiceUnavailable
+	}
+	render.Status(r, code)
+	render.JSON(w, r, healthResult{
+		Status: status,
+		Outbox: outboxResult{
+			Backlog:                stats.Backlog,
+			OldestUnsentAgeSeconds: oldestAge.Seconds(),
+			CheckedAt:              stats.CheckedAt,
+		},
+	})
+}
+
+func (c *Controller) Register(r chi.Router) {
+	r.Get("/health", c.health)
+}
+
+func NewController(monitor OutboxMonitor, maxOutboxAge, statsInterval time.Duration) (controller *Controller) {
+	controller = &Controller{
+		monitor:       monitor,
+		maxOutboxAge:  maxOutboxAge,
+		statsInterval: statsInterval,
+	}
+	return
+}
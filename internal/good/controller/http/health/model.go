@@ -0,0 +1,14 @@
+package health
+
+import "time"
+
+type outboxResult struct {
+	Backlog                int64     `json:"backlog"`
+	OldestUnsentAgeSeconds float64   `json:"oldestUnsentAgeSeconds"`
+	CheckedAt              time.Time `json:"checkedAt"`
+}
+
+type healthResult struct {
+	Status string       `json:"status"`
+	Outbox outboxResult `json:"outbox"`
+}
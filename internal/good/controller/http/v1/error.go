@@ -20,6 +20,31 @@ var (
 		Message: "errors.badRequest",
 	}
 
+	idempotencyKeyReusedError = &errorResponse{
+		Code:    6,
+		Message: "errors.idempotencyKey.reused",
+	}
+
+	conflictError = &errorResponse{
+		Code:    7,
+		Message: "errors.good.conflict",
+	}
+
+	notRemovedError = &errorResponse{
+		Code:    8,
+		Message: "errors.good.notRemoved",
+	}
+
+	projectNotFoundError = &errorResponse{
+		Code:    9,
+		Message: "errors.project.notFound",
+	}
+
+	requestTooLargeError = &errorResponse{
+		Code:    10,
+		Message: "errors.requestTooLarge",
+	}
+
 	internalServerError = &errorResponse{
 		Code:    5,
 		Message: "errors.internalServerError",
@@ -46,6 +71,21 @@ func (h *errorHandler) wrap(f errorHandlerFunc) http.HandlerFunc {
 			case errors.Is(err, domain.ErrGoodNotFound):
 				render.Status(r, http.StatusNotFound)
 				render.JSON(w, r, notFoundError)
+			case errors.Is(err, domain.ErrProjectNotFound):
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, projectNotFoundError)
+			case errors.Is(err, domain.ErrIdempotencyKeyReused):
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, idempotencyKeyReusedError)
+			case errors.Is(err, domain.ErrConflict):
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, conflictError)
+			case errors.Is(err, domain.ErrGoodNotRemoved):
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, notRemovedError)
+			case errors.Is(err, domain.ErrRequestTooLarge):
+				render.Status(r, http.StatusRequestEntityTooLarge)
+				render.JSON(w, r, requestTooLargeError)
 			case errors.Is(err, domain.ErrBadRequest):
 				render.Status(r, http.StatusBadRequest)
 				render.JSON(w, r, badRequest)
@@ -0,0 +1,130 @@
+package v1
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"goods-service/internal/good/domain"
+)
+
+const (
+	maxImportRows     = 10000
+	maxImportLineSize = 64 * 1024
+	maxImportBodySize = 16 << 20
+)
+
+// decodeNDJSON reads one JSON object per line, up to maxImportRows. Blank
+// lines are skipped and do not count as rows.
+func decodeNDJSON(r io.Reader) (goods []domain.ImportGood, rowErrors []domain.ImportError, err error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxImportLineSize)
+	row := 0
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		row++
+		if row > maxImportRows {
+			err = fmt.Errorf("%w: more than %d rows", domain.ErrBadRequest, maxImportRows)
+			return
+		}
+		var req importGoodRequest
+		decodeErr := json.Unmarshal(line, &req)
+		if decodeErr != nil {
+			rowErrors = append(rowErrors, domain.ImportError{
+				Row:     row,
+				Message: fmt.Sprintf("invalid json: %v", decodeErr),
+			})
+			continue
+		}
+		goods = append(goods, domain.ImportGood{
+			Row:         row,
+			Name:        req.Name,
+			Description: req.Description,
+		})
+	}
+	if err = scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			err = fmt.Errorf("%w: row %d is longer than %d bytes", domain.ErrBadRequest, row+1, maxImportLineSize)
+			return
+		}
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			err = fmt.Errorf("%w: body is larger than %d bytes", domain.ErrBadRequest, maxBytesErr.Limit)
+			return
+		}
+		err = fmt.Errorf("scan: %w", err)
+		return
+	}
+	return
+}
+
+// decodeCSV reads up to maxImportRows records after a header row naming
+// the columns. The name column is required, description is optional and
+// others are ignored.
+func decodeCSV(r io.Reader) (goods []domain.ImportGood, rowErrors []domain.ImportError, err error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			err = fmt.Errorf("%w: missing csv header", domain.ErrBadRequest)
+			return
+		}
+		err = fmt.Errorf("%w: read csv header: %v", domain.ErrBadRequest, err)
+		return
+	}
+	nameColumn, descriptionColumn := -1, -1
+	for i, column := range header {
+		switch strings.ToLower(strings.TrimSpace(column)) {
+		case domain.FieldName:
+			nameColumn = i
+		case domain.FieldDescription:
+			descriptionColumn = i
+		}
+	}
+	if nameColumn < 0 {
+		err = fmt.Errorf("%w: csv header has no %s column", domain.ErrBadRequest, domain.FieldName)
+		return
+	}
+	for row := 1; ; row++ {
+		var record []string
+		record, err = reader.Read()
+		if errors.Is(err, io.EOF) {
+			err = nil
+			return
+		}
+		if row > maxImportRows {
+			err = fmt.Errorf("%w: more than %d rows", domain.ErrBadRequest, maxImportRows)
+			return
+		}
+		if err != nil {
+			err = fmt.Errorf("%w: read csv row %d: %v", domain.ErrBadRequest, row, err)
+			return
+		}
+		if nameColumn >= len(record) {
+			rowErrors = append(rowErrors, domain.ImportError{
+				Row:     row,
+				Message: fmt.Sprintf("missing %s column", domain.FieldName),
+			})
+			continue
+		}
+		importGood := domain.ImportGood{
+			Row:  row,
+			Name: record[nameColumn],
+		}
+		if descriptionColumn >= 0 && descriptionColumn < len(record) {
+			importGood.Description = record[descriptionColumn]
+		}
+		goods = append(goods, importGood)
+	}
+}
@@ -15,6 +15,15 @@ type reprioritizeRequest struct {
 	NewPriority int32 `json:"newPriority"`
 }
 
+type reorderRequest struct {
+	IDs []int64 `json:"ids"`
+}
+
+type importGoodRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
 type goodPriority struct {
 	ID       int64 `json:"id"`
 	Priority int32 `json:"priority"`
@@ -30,11 +39,18 @@ type removeGoodResponse struct {
 	Removed   bool  `json:"removed"`
 }
 
+type purgeGoodResponse struct {
+	ID        int64 `json:"id"`
+	ProjectID int64 `json:"projectId"`
+	Purged    bool  `json:"purged"`
+}
+
 type meta struct {
-	Total   int32 `json:"total"`
-	Removed int32 `json:"removed"`
-	Limit   int32 `json:"limit"`
-	Offset  int32 `json:"offset"`
+	Total      int32  `json:"total"`
+	Removed    int32  `json:"removed"`
+	Limit      int32  `json:"limit"`
+	Offset     int32  `json:"offset"`
+	NextCursor string `json:"nextCursor,omitempty"`
 }
 
 type goodResult struct {
@@ -45,9 +61,45 @@ type goodResult struct {
 	Priority    int32     `json:"priority"`
 	Removed     bool      `json:"removed"`
 	CreatedAt   time.Time `json:"created_at"`
+	Version     int64     `json:"version"`
 }
 
 type goodsListResult struct {
 	Meta  meta         `json:"meta"`
 	Goods []goodResult `json:"goods"`
 }
+
+type logResult struct {
+	EventID     string    `json:"eventId"`
+	ID          int64     `json:"id"`
+	ProjectID   int64     `json:"projectId"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Priority    int32     `json:"priority"`
+	Removed     bool      `json:"removed"`
+	EventType   string    `json:"eventType"`
+	EventTime   time.Time `json:"eventTime"`
+	Version     int64     `json:"version"`
+
+	ChangedFields []string `json:"changedFields,omitempty"`
+}
+
+type historyResult struct {
+	Logs       []logResult `json:"logs"`
+	NextCursor string      `json:"nextCursor,omitempty"`
+}
+
+type importedGood struct {
+	Row int   `json:"row"`
+	ID  int64 `json:"id"`
+}
+
+type importError struct {
+	Row     int    `json:"row"`
+	Message string `json:"message"`
+}
+
+type importResult struct {
+	Created []importedGood `json:"created"`
+	Errors  []importError  `json:"errors"`
+}
@@ -1,11 +1,21 @@
 package v1
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"math"
+	"mime"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
@@ -18,15 +28,39 @@ const (
 	projectIDParam = "projectId"
 	limitParam     = "limit"
 	offsetParam    = "offset"
+	fromParam      = "from"
+	toParam        = "to"
+	removedParam   = "removed"
+	nameParam      = "name"
+	cursorParam    = "cursor"
+	sortParam      = "sort"
+
+	idempotencyKeyHeader = "Idempotency-Key"
+	etagHeader           = "ETag"
+	ifMatchHeader        = "If-Match"
+	contentTypeHeader    = "Content-Type"
+
+	mergePatchMediaType = "application/merge-patch+json"
+	ndjsonMediaType     = "application/x-ndjson"
+	csvMediaType        = "text/csv"
+
+	defaultHistoryLimit = 50
+	maxCreateBodySize   = 64 << 10
 )
 
 type GoodService interface {
 	Create(ctx context.Context, createGood domain.CreateGood) (good domain.Good, err error)
+	Get(ctx context.Context, getGood domain.GetGood) (good domain.Good, err error)
 	Update(ctx context.Context, updateGood domain.UpdateGood) (good domain.Good, err error)
-	Delete(ctx context.Context, deleteGood domain.DeleteGood) (err error)
+	Delete(ctx context.Context, deleteGood domain.DeleteGood) (version int64, err error)
+	Restore(ctx context.Context, restoreGood domain.RestoreGood) (good domain.Good, err error)
+	Purge(ctx context.Context, purgeGood domain.PurgeGood) (err error)
 	List(ctx context.Context, listGoods domain.ListGoods) (goodsList domain.GoodsList, err error)
 	Reprioritize(ctx context.Context, reprioritizeGood domain.ReprioritizeGood) (
-		goodPriorities []domain.GoodPriority, err error)
+		goodPriorities []domain.GoodPriority, version int64, err error)
+	Reorder(ctx context.Context, reorderGoods domain.ReorderGoods) (goodPriorities []domain.GoodPriority, err error)
+	History(ctx context.Context, listLogs domain.ListLogs) (logsList domain.LogsList, err error)
+	Import(ctx context.Context, importGoods domain.ImportGoods) (report domain.ImportReport, err error)
 }
 
 type Controller struct {
@@ -34,7 +68,7 @@ type Controller struct {
 }
 
 func (c *Controller) create(w http.ResponseWriter, r *http.Request) (err error) {
-	projectIDStr := chi.URLParam(r, projectIDParam)
+	projectIDStr := r.URL.Query().Get(projectIDParam)
 	if projectIDStr == "" {
 		err = fmt.Errorf("%w: missing url param: projectId", domain.ErrBadRequest)
 		return
@@ -48,21 +82,68 @@ func (c *Controller) create(w http.ResponseWriter, r *http.Request) (err error)
 		err = fmt.Errorf("parse int: %w", err)
 		return
 	}
+	defer r.Body.Close()
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxCreateBodySize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			err = fmt.Errorf("%w: body is larger than %d bytes", domain.ErrRequestTooLarge, maxBytesErr.Limit)
+			return
+		}
+		err = fmt.Errorf("read body: %w", err)
+		return
+	}
 	req := new(createGoodRequest)
-	err = render.DecodeJSON(r.Body, req)
+	err = render.DecodeJSON(bytes.NewReader(body), req)
 	if err != nil {
 		err = fmt.Errorf("decode json: %w", err)
 		return
 	}
-	defer r.Body.Close()
+	bodyHash := sha256.Sum256(body)
 	good, err := c.service.Create(r.Context(), domain.CreateGood{
-		ProjectID: projectID,
-		Name:      req.Name,
+		ProjectID:      projectID,
+		Name:           req.Name,
+		IdempotencyKey: r.Header.Get(idempotencyKeyHeader),
+		RequestHash:    hex.EncodeToString(bodyHash[:]),
 	})
 	if err != nil {
 		err = fmt.Errorf("service create: %w", err)
 		return
 	}
+	setETag(w, good.Version)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, goodResult{
+		ID:          good.ID,
+		ProjectID:   good.ProjectID,
+		Name:        good.Name,
+		Description: good.Description,
+		Priority:    good.Priority,
+		Removed:     good.Removed,
+		CreatedAt:   good.CreatedAt,
+		Version:     good.Version,
+	})
+	return
+}
+
+func (c *Controller) get(w http.ResponseWriter, r *http.Request) (err error) {
+	var (
+		goodID    int64
+		projectID int64
+	)
+	goodID, projectID, err = getURLParams(r)
+	if err != nil {
+		err = fmt.Errorf("get url params: %w", err)
+		return
+	}
+	good, err := c.service.Get(r.Context(), domain.GetGood{
+		ID:        goodID,
+		ProjectID: projectID,
+	})
+	if err != nil {
+		err = fmt.Errorf("service get: %w", err)
+		return
+	}
+	setETag(w, good.Version)
 	render.Status(r, http.StatusOK)
 	render.JSON(w, r, goodResult{
 		ID:          good.ID,
@@ -70,7 +151,9 @@ func (c *Controller) create(w http.ResponseWriter, r *http.Request) (err error)
 		Name:        good.Name,
 		Description: good.Description,
 		Priority:    good.Priority,
+		Removed:     good.Removed,
 		CreatedAt:   good.CreatedAt,
+		Version:     good.Version,
 	})
 	return
 }
@@ -85,23 +168,40 @@ func (c *Controller) update(w http.ResponseWriter, r *http.Request) (err error)
 		err = fmt.Errorf("get url params: %w", err)
 		return
 	}
-	req := new(updateGoodRequest)
-	err = render.DecodeJSON(r.Body, req)
+	version, err := parseIfMatch(r)
 	if err != nil {
-		err = fmt.Errorf("decode json: %w", err)
+		err = fmt.Errorf("parse if match: %w", err)
 		return
 	}
 	defer r.Body.Close()
-	good, err := c.service.Update(r.Context(), domain.UpdateGood{
-		ID:          goodID,
-		ProjectID:   projectID,
-		Name:        req.Name,
-		Description: req.Description,
-	})
+	updateGood := domain.UpdateGood{
+		ID:        goodID,
+		ProjectID: projectID,
+		Version:   version,
+	}
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get(contentTypeHeader))
+	if mediaType == mergePatchMediaType {
+		updateGood.Name, updateGood.Description, err = decodeMergePatch(r)
+		if err != nil {
+			err = fmt.Errorf("decode merge patch: %w", err)
+			return
+		}
+	} else {
+		req := new(updateGoodRequest)
+		err = render.DecodeJSON(r.Body, req)
+		if err != nil {
+			err = fmt.Errorf("%w: decode json: %v", domain.ErrBadRequest, err)
+			return
+		}
+		updateGood.Name = domain.OptionalString{Set: true, Value: req.Name}
+		updateGood.Description = domain.OptionalString{Set: true, Value: req.Description}
+	}
+	good, err := c.service.Update(r.Context(), updateGood)
 	if err != nil {
 		err = fmt.Errorf("service update: %w", err)
 		return
 	}
+	setETag(w, good.Version)
 	render.Status(r, http.StatusOK)
 	render.JSON(w, r, goodResult{
 		ID:          good.ID,
@@ -109,7 +209,9 @@ func (c *Controller) update(w http.ResponseWriter, r *http.Request) (err error)
 		Name:        good.Name,
 		Description: good.Description,
 		Priority:    good.Priority,
+		Removed:     good.Removed,
 		CreatedAt:   good.CreatedAt,
+		Version:     good.Version,
 	})
 	return
 }
@@ -124,14 +226,21 @@ func (c *Controller) remove(w http.ResponseWriter, r *http.Request) (err error)
 		err = fmt.Errorf("get url params: %w", err)
 		return
 	}
-	err = c.service.Delete(r.Context(), domain.DeleteGood{
+	version, err := parseIfMatch(r)
+	if err != nil {
+		err = fmt.Errorf("parse if match: %w", err)
+		return
+	}
+	version, err = c.service.Delete(r.Context(), domain.DeleteGood{
 		ID:        goodID,
 		ProjectID: projectID,
+		Version:   version,
 	})
 	if err != nil {
 		err = fmt.Errorf("delete good: %w", err)
 		return
 	}
+	setETag(w, version)
 	render.Status(r, http.StatusOK)
 	render.JSON(w, r, removeGoodResponse{
 		ID:        goodID,
@@ -141,43 +250,131 @@ func (c *Controller) remove(w http.ResponseWriter, r *http.Request) (err error)
 	return
 }
 
+func (c *Controller) restore(w http.ResponseWriter, r *http.Request) (err error) {
+	var (
+		goodID    int64
+		projectID int64
+	)
+	goodID, projectID, err = getURLParams(r)
+	if err != nil {
+		err = fmt.Errorf("get url params: %w", err)
+		return
+	}
+	version, err := parseIfMatch(r)
+	if err != nil {
+		err = fmt.Errorf("parse if match: %w", err)
+		return
+	}
+	good, err := c.service.Restore(r.Context(), domain.RestoreGood{
+		ID:        goodID,
+		ProjectID: projectID,
+		Version:   version,
+	})
+	if err != nil {
+		err = fmt.Errorf("service restore: %w", err)
+		return
+	}
+	setETag(w, good.Version)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, goodResult{
+		ID:          good.ID,
+		ProjectID:   good.ProjectID,
+		Name:        good.Name,
+		Description: good.Description,
+		Priority:    good.Priority,
+		Removed:     good.Removed,
+		CreatedAt:   good.CreatedAt,
+		Version:     good.Version,
+	})
+	return
+}
+
+func (c *Controller) purge(w http.ResponseWriter, r *http.Request) (err error) {
+	var (
+		goodID    int64
+		projectID int64
+	)
+	goodID, projectID, err = getURLParams(r)
+	if err != nil {
+		err = fmt.Errorf("get url params: %w", err)
+		return
+	}
+	version, err := parseIfMatch(r)
+	if err != nil {
+		err = fmt.Errorf("parse if match: %w", err)
+		return
+	}
+	err = c.service.Purge(r.Context(), domain.PurgeGood{
+		ID:        goodID,
+		ProjectID: projectID,
+		Version:   version,
+	})
+	if err != nil {
+		err = fmt.Errorf("service purge: %w", err)
+		return
+	}
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, purgeGoodResponse{
+		ID:        goodID,
+		ProjectID: projectID,
+		Purged:    true,
+	})
+	return
+}
+
 func (c *Controller) list(w http.ResponseWriter, r *http.Request) (err error) {
 	var (
 		limit  int64
 		offset int64
 	)
-	limitStr := chi.URLParam(r, limitParam)
+	limitStr := r.URL.Query().Get(limitParam)
 	if limitStr == "" {
 		err = fmt.Errorf("%w: missing url param: limit", domain.ErrBadRequest)
 		return
 	}
-	limit, err = strconv.ParseInt(limitStr, 10, 64)
+	limit, err = strconv.ParseInt(limitStr, 10, 32)
 	if err != nil {
-		if errors.Is(err, strconv.ErrSyntax) {
+		if errors.Is(err, strconv.ErrSyntax) || errors.Is(err, strconv.ErrRange) {
 			err = fmt.Errorf("%w: limit has invalid syntax", domain.ErrBadRequest)
 			return
 		}
 		err = fmt.Errorf("parse int: %w", err)
 		return
 	}
-	offsetStr := chi.URLParam(r, offsetParam)
-	if offsetStr == "" {
+	query := r.URL.Query()
+	cursor := query.Get(cursorParam)
+	offsetStr := query.Get(offsetParam)
+	if offsetStr == "" && cursor == "" {
 		err = fmt.Errorf("%w: missing url param: offset", domain.ErrBadRequest)
 		return
 	}
-	offset, err = strconv.ParseInt(offsetStr, 10, 64)
-	if err != nil {
-		if errors.Is(err, strconv.ErrSyntax) {
-			err = fmt.Errorf("%w: offset has invalid syntax", err)
+	if offsetStr != "" {
+		offset, err = strconv.ParseInt(offsetStr, 10, 32)
+		if err != nil {
+			if errors.Is(err, strconv.ErrSyntax) || errors.Is(err, strconv.ErrRange) {
+				err = fmt.Errorf("%w: offset has invalid syntax", domain.ErrBadRequest)
+				return
+			}
+			err = fmt.Errorf("parse int: %w", err)
 			return
 		}
-		err = fmt.Errorf("parse int: %w", err)
-		return
 	}
-	goodsList, err := c.service.List(r.Context(), domain.ListGoods{
+	listGoods := domain.ListGoods{
+		Name:   query.Get(nameParam),
+		Sort:   domain.GoodsSort(query.Get(sortParam)),
+		Cursor: cursor,
 		Limit:  int32(limit),
 		Offset: int32(offset),
-	})
+	}
+	listGoods.ProjectID, err = parseInt64Param(query, projectIDParam)
+	if err != nil {
+		return
+	}
+	listGoods.Removed, err = parseBoolParam(query, removedParam)
+	if err != nil {
+		return
+	}
+	goodsList, err := c.service.List(r.Context(), listGoods)
 	if err != nil {
 		err = fmt.Errorf("service list: %w", err)
 		return
@@ -190,14 +387,17 @@ func (c *Controller) list(w http.ResponseWriter, r *http.Request) (err error) {
 			Name:        good.Name,
 			Description: good.Description,
 			Priority:    good.Priority,
+			Removed:     good.Removed,
 			CreatedAt:   good.CreatedAt,
+			Version:     good.Version,
 		})
 	}
 	meta := meta{
-		Total:   goodsList.Meta.Total,
-		Removed: goodsList.Meta.Removed,
-		Limit:   goodsList.Meta.Limit,
-		Offset:  goodsList.Meta.Offset,
+		Total:      goodsList.Meta.Total,
+		Removed:    goodsList.Meta.Removed,
+		Limit:      goodsList.Meta.Limit,
+		Offset:     goodsList.Meta.Offset,
+		NextCursor: goodsList.Meta.NextCursor,
 	}
 	render.Status(r, http.StatusOK)
 	render.JSON(w, r, goodsListResult{
@@ -210,10 +410,16 @@ func (c *Controller) list(w http.ResponseWriter, r *http.Request) (err error) {
 func (c *Controller) Register(r chi.Router) {
 	eh := errorHandler{}
 	r.Post("/good/create", eh.wrap(c.create))
+	r.Get("/good/get", eh.wrap(c.get))
 	r.Patch("/good/update", eh.wrap(c.update))
 	r.Delete("/good/remove", eh.wrap(c.remove))
+	r.Patch("/good/restore", eh.wrap(c.restore))
+	r.Delete("/good/purge", eh.wrap(c.purge))
 	r.Get("/good/list", eh.wrap(c.list))
 	r.Patch("/good/reprioritize", eh.wrap(c.reprioritize))
+	r.Patch("/good/reorder", eh.wrap(c.reorder))
+	r.Get("/good/history", eh.wrap(c.history))
+	r.Post("/good/import", eh.wrap(c.importGoods))
 }
 
 func (c *Controller) reprioritize(w http.ResponseWriter, r *http.Request) (err error) {
@@ -226,6 +432,11 @@ func (c *Controller) reprioritize(w http.ResponseWriter, r *http.Request) (err e
 		err = fmt.Errorf("get url params: %w", err)
 		return
 	}
+	version, err := parseIfMatch(r)
+	if err != nil {
+		err = fmt.Errorf("parse if match: %w", err)
+		return
+	}
 	req := new(reprioritizeRequest)
 	err = render.DecodeJSON(r.Body, req)
 	if err != nil {
@@ -233,18 +444,63 @@ func (c *Controller) reprioritize(w http.ResponseWriter, r *http.Request) (err e
 		return
 	}
 	defer r.Body.Close()
-	goodPriorities, err := c.service.Reprioritize(r.Context(), domain.ReprioritizeGood{
+	goodPriorities, version, err := c.service.Reprioritize(r.Context(), domain.ReprioritizeGood{
 		ID:          goodID,
 		ProjectID:   projectID,
 		NewPriority: req.NewPriority,
+		Version:     version,
 	})
 	if err != nil {
 		err = fmt.Errorf("reprioritize: %w", err)
 		return
 	}
+	setETag(w, version)
 
 	result := reprioritizeResult{}
-	result.Priotities = make([]goodPriority, len(goodPriorities))
+	result.Priotities = make([]goodPriority, 0, len(goodPriorities))
+	for _, priority := range goodPriorities {
+		result.Priotities = append(result.Priotities, goodPriority{
+			ID:       priority.ID,
+			Priority: priority.Priority,
+		})
+	}
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, result)
+	return
+}
+
+func (c *Controller) reorder(w http.ResponseWriter, r *http.Request) (err error) {
+	projectIDStr := r.URL.Query().Get(projectIDParam)
+	if projectIDStr == "" {
+		err = fmt.Errorf("%w: missing url param: projectId", domain.ErrBadRequest)
+		return
+	}
+	projectID, err := strconv.ParseInt(projectIDStr, 10, 64)
+	if err != nil {
+		if errors.Is(err, strconv.ErrSyntax) {
+			err = fmt.Errorf("%w: projectId has invalid syntax", domain.ErrBadRequest)
+			return
+		}
+		err = fmt.Errorf("parse int: %w", err)
+		return
+	}
+	req := new(reorderRequest)
+	err = render.DecodeJSON(r.Body, req)
+	if err != nil {
+		err = fmt.Errorf("decode json: %w", err)
+		return
+	}
+	defer r.Body.Close()
+	goodPriorities, err := c.service.Reorder(r.Context(), domain.ReorderGoods{
+		ProjectID: projectID,
+		IDs:       req.IDs,
+	})
+	if err != nil {
+		err = fmt.Errorf("reorder: %w", err)
+		return
+	}
+	result := reprioritizeResult{}
+	result.Priotities = make([]goodPriority, 0, len(goodPriorities))
 	for _, priority := range goodPriorities {
 		result.Priotities = append(result.Priotities, goodPriority{
 			ID:       priority.ID,
@@ -256,6 +512,138 @@ func (c *Controller) reprioritize(w http.ResponseWriter, r *http.Request) (err e
 	return
 }
 
+// importGoods reads goods from an NDJSON or CSV body, one good per line or
+// record. Rows that cannot be read are reported along with the ones the
+// service rejects.
+func (c *Controller) importGoods(w http.ResponseWriter, r *http.Request) (err error) {
+	projectIDStr := r.URL.Query().Get(projectIDParam)
+	if projectIDStr == "" {
+		err = fmt.Errorf("%w: missing url param: projectId", domain.ErrBadRequest)
+		return
+	}
+	projectID, err := strconv.ParseInt(projectIDStr, 10, 64)
+	if err != nil {
+		if errors.Is(err, strconv.ErrSyntax) {
+			err = fmt.Errorf("%w: projectId has invalid syntax", domain.ErrBadRequest)
+			return
+		}
+		err = fmt.Errorf("parse int: %w", err)
+		return
+	}
+	defer r.Body.Close()
+	body := http.MaxBytesReader(w, r.Body, maxImportBodySize)
+	importGoods := domain.ImportGoods{
+		ProjectID: projectID,
+	}
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get(contentTypeHeader))
+	switch mediaType {
+	case ndjsonMediaType, "application/ndjson":
+		importGoods.Goods, importGoods.Errors, err = decodeNDJSON(body)
+	case csvMediaType:
+		importGoods.Goods, importGoods.Errors, err = decodeCSV(body)
+	default:
+		err = fmt.Errorf("%w: Content-Type must be %s or %s", domain.ErrBadRequest, ndjsonMediaType, csvMediaType)
+		return
+	}
+	if err != nil {
+		err = fmt.Errorf("decode %s: %w", mediaType, err)
+		return
+	}
+	report, err := c.service.Import(r.Context(), importGoods)
+	if err != nil {
+		err = fmt.Errorf("service import: %w", err)
+		return
+	}
+	result := importResult{
+		Created: make([]importedGood, 0, len(report.Created)),
+		Errors:  make([]importError, 0, len(report.Errors)),
+	}
+	for _, created := range report.Created {
+		result.Created = append(result.Created, importedGood{
+			Row: created.Row,
+			ID:  created.ID,
+		})
+	}
+	for _, importErr := range report.Errors {
+		result.Errors = append(result.Errors, importError{
+			Row:     importErr.Row,
+			Message: importErr.Message,
+		})
+	}
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, result)
+	return
+}
+
+func (c *Controller) history(w http.ResponseWriter, r *http.Request) (err error) {
+	query := r.URL.Query()
+	listLogs := domain.ListLogs{
+		Name:   query.Get(nameParam),
+		Cursor: query.Get(cursorParam),
+		Limit:  defaultHistoryLimit,
+	}
+	listLogs.GoodID, err = parseInt64Param(query, goodIDParam)
+	if err != nil {
+		return
+	}
+	listLogs.ProjectID, err = parseInt64Param(query, projectIDParam)
+	if err != nil {
+		return
+	}
+	if query.Get(limitParam) != "" {
+		var limit int64
+		limit, err = parseInt64Param(query, limitParam)
+		if err != nil {
+			return
+		}
+		if limit < math.MinInt32 || limit > math.MaxInt32 {
+			err = fmt.Errorf("%w: limit is out of range", domain.ErrBadRequest)
+			return
+		}
+		listLogs.Limit = int32(limit)
+	}
+	listLogs.From, err = parseTimeParam(query, fromParam)
+	if err != nil {
+		return
+	}
+	listLogs.To, err = parseTimeParam(query, toParam)
+	if err != nil {
+		return
+	}
+	listLogs.Removed, err = parseBoolParam(query, removedParam)
+	if err != nil {
+		return
+	}
+	logsList, err := c.service.History(r.Context(), listLogs)
+	if err != nil {
+		err = fmt.Errorf("service history: %w", err)
+		return
+	}
+	logResults := make([]logResult, 0, len(logsList.Logs))
+	for _, log := range logsList.Logs {
+		logResults = append(logResults, logResult{
+			EventID:     log.EventID,
+			ID:          log.ID,
+			ProjectID:   log.ProjectID,
+			Name:        log.Name,
+			Description: log.Description,
+			Priority:    log.Priority,
+			Removed:     log.Removed,
+			EventType:   string(log.EventType),
+			EventTime:   log.EventTime,
+			Version:     log.Version,
+
+			ChangedFields: log.ChangedFields,
+		})
+	}
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, historyResult{
+		Logs:       logResults,
+		NextCursor: logsList.NextCursor,
+	})
+	return
+}
+
 func NewController(service GoodService) (controller *Controller) {
 	controller = &Controller{
 		service: service,
@@ -263,8 +651,72 @@ func NewController(service GoodService) (controller *Controller) {
 	return
 }
 
+// decodeMergePatch reads a JSON Merge Patch (RFC 7396) of a good: members
+// that are absent are left unset and null members clear the field.
+func decodeMergePatch(r *http.Request) (name, description domain.OptionalString, err error) {
+	var patch map[string]json.RawMessage
+	err = json.NewDecoder(r.Body).Decode(&patch)
+	if err != nil {
+		err = fmt.Errorf("%w: merge patch must be a JSON object: %v", domain.ErrBadRequest, err)
+		return
+	}
+	name, err = patchString(patch, domain.FieldName)
+	if err != nil {
+		return
+	}
+	description, err = patchString(patch, domain.FieldDescription)
+	if err != nil {
+		return
+	}
+	return
+}
+
+func patchString(patch map[string]json.RawMessage, field string) (value domain.OptionalString, err error) {
+	raw, ok := patch[field]
+	if !ok {
+		return
+	}
+	value.Set = true
+	if string(raw) == "null" {
+		value.Null = true
+		return
+	}
+	err = json.Unmarshal(raw, &value.Value)
+	if err != nil {
+		err = fmt.Errorf("%w: %s must be a string or null", domain.ErrBadRequest, field)
+		return
+	}
+	return
+}
+
+// setETag sets a strong ETag holding the version of the good.
+func setETag(w http.ResponseWriter, version int64) {
+	w.Header().Set(etagHeader, strconv.Quote(strconv.FormatInt(version, 10)))
+}
+
+// parseIfMatch returns the version required by the If-Match header, or
+// zero if the header is missing or "*". Weak and multiple ETags are
+// rejected, since If-Match uses the strong comparison.
+func parseIfMatch(r *http.Request) (version int64, err error) {
+	value := strings.TrimSpace(r.Header.Get(ifMatchHeader))
+	if value == "" || value == "*" {
+		return
+	}
+	unquoted, err := strconv.Unquote(value)
+	if err != nil {
+		err = fmt.Errorf("%w: If-Match must be a single strong ETag", domain.ErrBadRequest)
+		return
+	}
+	version, err = strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version <= 0 {
+		err = fmt.Errorf("%w: If-Match has invalid syntax", domain.ErrBadRequest)
+		return
+	}
+	return
+}
+
 func getURLParams(r *http.Request) (goodID int64, projectID int64, err error) {
-	goodIDStr := chi.URLParam(r, goodIDParam)
+	goodIDStr := r.URL.Query().Get(goodIDParam)
 	if goodIDStr == "" {
 		err = fmt.Errorf("%w: missing url param: id", domain.ErrBadRequest)
 		return
@@ -278,7 +730,7 @@ func getURLParams(r *http.Request) (goodID int64, projectID int64, err error) {
 		err = fmt.Errorf("parse int: %w", err)
 		return
 	}
-	projectIDStr := chi.URLParam(r, projectIDParam)
+	projectIDStr := r.URL.Query().Get(projectIDParam)
 	if projectIDStr == "" {
 		err = fmt.Errorf("%w: missing url param: projectId", domain.ErrBadRequest)
 		return
@@ -294,3 +746,46 @@ func getURLParams(r *http.Request) (goodID int64, projectID int64, err error) {
 	}
 	return
 }
+
+// parseInt64Param returns zero when the optional param is absent.
+func parseInt64Param(query url.Values, name string) (value int64, err error) {
+	str := query.Get(name)
+	if str == "" {
+		return
+	}
+	value, err = strconv.ParseInt(str, 10, 64)
+	if err != nil {
+		err = fmt.Errorf("%w: %s has invalid syntax", domain.ErrBadRequest, name)
+		return
+	}
+	return
+}
+
+// parseTimeParam returns the zero time when the optional param is absent.
+func parseTimeParam(query url.Values, name string) (value time.Time, err error) {
+	str := query.Get(name)
+	if str == "" {
+		return
+	}
+	value, err = time.Parse(time.RFC3339, str)
+	if err != nil {
+		err = fmt.Errorf("%w: %s must be an RFC 3339 timestamp", domain.ErrBadRequest, name)
+		return
+	}
+	return
+}
+
+// parseBoolParam returns nil when the optional param is absent.
+func parseBoolParam(query url.Values, name string) (value *bool, err error) {
+	str := query.Get(name)
+	if str == "" {
+		return
+	}
+	b, err := strconv.ParseBool(str)
+	if err != nil {
+		err = fmt.Errorf("%w: %s has invalid syntax", domain.ErrBadRequest, name)
+		return
+	}
+	value = &b
+	return
+}
@@ -0,0 +1,19 @@
+package domain
+
+// CacheInvalidation describes the cache entries a write made stale: the
+// goods with the given ids and, if Lists is set, every goods list of the
+// project.
+type CacheInvalidation struct {
+	ProjectID int64
+	IDs       []int64
+	Lists     bool
+}
+
+// CacheVersion is the state of the cache tiers a miss was seen at. A value
+// read from the storage after the miss is only cached if nothing it covers
+// was invalidated since, otherwise a write committed during the read would
+// be cached as stale data.
+type CacheVersion struct {
+	Local  uint64
+	Remote int64
+}
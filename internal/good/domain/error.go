@@ -3,6 +3,12 @@ package domain
 import "errors"
 
 var (
-	ErrGoodNotFound = errors.New("good not found")
-	ErrBadRequest   = errors.New("bad request")
+	ErrGoodNotFound         = errors.New("good not found")
+	ErrProjectNotFound      = errors.New("project not found")
+	ErrBadRequest           = errors.New("bad request")
+	ErrCacheMiss            = errors.New("cache miss")
+	ErrIdempotencyKeyReused = errors.New("idempotency key reused")
+	ErrConflict             = errors.New("version conflict")
+	ErrGoodNotRemoved       = errors.New("good not removed")
+	ErrRequestTooLarge      = errors.New("request too large")
 )
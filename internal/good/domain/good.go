@@ -1,6 +1,10 @@
 package domain
 
-import "time"
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
 
 type Good struct {
 	ID          int64
@@ -10,29 +14,115 @@ type Good struct {
 	Priority    int32
 	Removed     bool
 	CreatedAt   time.Time
+	// Version is bumped by every write to the good.
+	Version int64
 }
 
 type CreateGood struct {
 	ProjectID int64
 	Name      string
+	// IdempotencyKey, if set, makes retries of the same request return the
+	// good created by the first one instead of creating another.
+	IdempotencyKey string
+	// RequestHash identifies the whole request the key came with, so that
+	// the key reused for a different request is told apart from a retry.
+	RequestHash string
 }
 
+type GetGood struct {
+	ID        int64
+	ProjectID int64
+}
+
+// Fields of a good that can be updated.
+const (
+	FieldName        = "name"
+	FieldDescription = "description"
+)
+
+// OptionalString is a field of a partial update. Unset fields are left
+// alone, Null clears a nullable field.
+type OptionalString struct {
+	Set   bool
+	Null  bool
+	Value string
+}
+
+// UpdateGood, DeleteGood and ReprioritizeGood apply only if the good is
+// still at Version, otherwise they fail with ErrConflict. A zero Version
+// applies unconditionally.
 type UpdateGood struct {
 	ID          int64
 	ProjectID   int64
-	Name        string
-	Description string
+	Name        OptionalString
+	Description OptionalString
+	Version     int64
 }
 
 type DeleteGood struct {
 	ID        int64
 	ProjectID int64
+	Version   int64
+}
+
+// RestoreGood brings a removed good back. It is placed at the end of the
+// project's priority order, like a newly created good.
+type RestoreGood struct {
+	ID        int64
+	ProjectID int64
+	Version   int64
+}
+
+// PurgeGood deletes a removed good permanently.
+type PurgeGood struct {
+	ID        int64
+	ProjectID int64
+	Version   int64
 }
 
 type ReprioritizeGood struct {
 	ID          int64
 	ProjectID   int64
 	NewPriority int32
+	Version     int64
+}
+
+type ReorderGoods struct {
+	ProjectID int64
+	IDs       []int64
+}
+
+// ImportGoods creates many goods of a project at once. Errors holds the
+// rows that could not be read, they are reported along with the rows that
+// fail validation.
+type ImportGoods struct {
+	ProjectID int64
+	Goods     []ImportGood
+	Errors    []ImportError
+}
+
+// ImportGood is a good to import. Row is its 1-based position in the
+// imported data.
+type ImportGood struct {
+	Row         int
+	Name        string
+	Description string
+}
+
+type ImportedGood struct {
+	Row int
+	ID  int64
+}
+
+type ImportError struct {
+	Row     int
+	Message string
+}
+
+// ImportReport tells what happened to every row of an import.
+type ImportReport struct {
+	Created []ImportedGood
+	Errors  []ImportError
 }
 
 type GoodPriority struct {
@@ -40,16 +130,42 @@ type GoodPriority struct {
 	Priority int32
 }
 
+type GoodsSort string
+
+const (
+	SortByPriority  GoodsSort = "priority"
+	SortByCreatedAt GoodsSort = "created_at"
+	SortByName      GoodsSort = "name"
+)
+
 type ListGoods struct {
-	Limit  int32
-	Offset int32
+	ProjectID int64
+	Removed   *bool
+	Name      string
+	Sort      GoodsSort
+	Cursor    string
+	Limit     int32
+	Offset    int32
+}
+
+// CacheKey identifies the list query, two queries with the same key return
+// the same page.
+func (l ListGoods) CacheKey() (key string) {
+	removed := "any"
+	if l.Removed != nil {
+		removed = strconv.FormatBool(*l.Removed)
+	}
+	key = fmt.Sprintf("project=%d&removed=%s&name=%q&sort=%s&cursor=%s&limit=%d&offset=%d",
+		l.ProjectID, removed, l.Name, l.Sort, l.Cursor, l.Limit, l.Offset)
+	return
 }
 
 type Meta struct {
-	Total   int32
-	Removed int32
-	Limit   int32
-	Offset  int32
+	Total      int32
+	Removed    int32
+	Limit      int32
+	Offset     int32
+	NextCursor string
 }
 
 type GoodsList struct {
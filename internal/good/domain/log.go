@@ -2,6 +2,18 @@ package domain
 
 import "time"
 
+// EventType tells which change of a good a log records.
+type EventType string
+
+const (
+	EventCreated       EventType = "created"
+	EventUpdated       EventType = "updated"
+	EventRemoved       EventType = "removed"
+	EventReprioritized EventType = "reprioritized"
+	EventRestored      EventType = "restored"
+	EventPurged        EventType = "purged"
+)
+
 type Log struct {
 	ID          int64
 	ProjectID   int64
@@ -9,5 +21,28 @@ type Log struct {
 	Description string
 	Priority    int32
 	Removed     bool
+	EventID     string
+	EventType   EventType
 	EventTime   time.Time
+	// Version is the version of the good the event produced, events of one
+	// good are ordered by it.
+	Version int64
+	// ChangedFields lists the fields an update event changed.
+	ChangedFields []string
+}
+
+type ListLogs struct {
+	GoodID    int64
+	ProjectID int64
+	From      time.Time
+	To        time.Time
+	Removed   *bool
+	Name      string
+	Cursor    string
+	Limit     int32
+}
+
+type LogsList struct {
+	Logs       []Log
+	NextCursor string
 }
@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// OutboxStats describes the events that wait in the outbox to be relayed.
+// OldestUnsentAt is zero when nothing waits, CheckedAt is zero until the
+// stats were collected once.
+type OutboxStats struct {
+	Backlog        int64
+	OldestUnsentAt time.Time
+	CheckedAt      time.Time
+}
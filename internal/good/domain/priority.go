@@ -0,0 +1,114 @@
+package domain
+
+import "sort"
+
+// MoveGood places the good with the given id at newPriority among goods of
+// one project and returns every good whose priority changed. Priorities of
+// the result are dense and unique, starting from 1: the goods between the
+// old and the new position shift by one, and a priority past the end moves
+// the good to the end.
+func MoveGood(goods []GoodPriority, id int64, newPriority int32) (changed []GoodPriority, err error) {
+	ordered := sortByPriority(goods)
+	index := -1
+	for i, good := range ordered {
+		if good.ID == id {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		err = ErrGoodNotFound
+		return
+	}
+	moved := ordered[index]
+	ordered = append(ordered[:index], ordered[index+1:]...)
+	position := int(newPriority) - 1
+	if position < 0 {
+		position = 0
+	}
+	if position > len(ordered) {
+		position = len(ordered)
+	}
+	ordered = append(ordered[:position], append([]GoodPriority{moved}, ordered[position:]...)...)
+	changed = renumber(ordered)
+	return
+}
+
+// RemoveGood takes the good with the given id out of the order and returns
+// every remaining good whose priority changed to close the gap.
+func RemoveGood(goods []GoodPriority, id int64) (changed []GoodPriority, err error) {
+	ordered := sortByPriority(goods)
+	index := -1
+	for i, good := range ordered {
+		if good.ID == id {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		err = ErrGoodNotFound
+		return
+	}
+	ordered = append(ordered[:index], ordered[index+1:]...)
+	changed = renumber(ordered)
+	return
+}
+
+// Reorder arranges the listed goods in the given order and returns every
+// good whose priority changed. The listed goods take over the positions
+// they currently occupy between them, so goods that are not listed keep
+// their place; listing all goods of the project reorders it completely.
+func Reorder(goods []GoodPriority, ids []int64) (changed []GoodPriority, err error) {
+	ordered := sortByPriority(goods)
+	positions := make(map[int64]int, len(ordered))
+	for i, good := range ordered {
+		positions[good.ID] = i
+	}
+	slots := make([]int, 0, len(ids))
+	for _, id := range ids {
+		position, ok := positions[id]
+		if !ok {
+			err = ErrGoodNotFound
+			return
+		}
+		slots = append(slots, position)
+	}
+	sort.Ints(slots)
+	reordered := make([]GoodPriority, len(ordered))
+	copy(reordered, ordered)
+	for i, id := range ids {
+		reordered[slots[i]] = ordered[positions[id]]
+	}
+	changed = renumber(reordered)
+	return
+}
+
+// sortByPriority returns a copy of goods ordered by priority, ties broken
+// by id.
+func sortByPriority(goods []GoodPriority) (ordered []GoodPriority) {
+	ordered = make([]GoodPriority, len(goods))
+	copy(ordered, goods)
+	sort.Slice(ordered, func(i, j int) bool {
+		if ordered[i].Priority != ordered[j].Priority {
+			return ordered[i].Priority < ordered[j].Priority
+		}
+		return ordered[i].ID < ordered[j].ID
+	})
+	return
+}
+
+// renumber assigns priorities 1..n in slice order and returns the goods
+// whose priority differs from the one they had.
+func renumber(ordered []GoodPriority) (changed []GoodPriority) {
+	changed = make([]GoodPriority, 0)
+	for i, good := range ordered {
+		priority := int32(i + 1)
+		if good.Priority != priority {
+			changed = append(changed, GoodPriority{
+				ID:       good.ID,
+				Priority: priority,
+			})
+		}
+	}
+	return
+}
@@ -0,0 +1,240 @@
+package domain
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+)
+
+// project is a random set of goods of one project. Priorities are not
+// necessarily dense or unique, like rows written before they were kept so.
+type project struct {
+	Goods []GoodPriority
+}
+
+func (project) Generate(r *rand.Rand, size int) reflect.Value {
+	n := 1 + r.Intn(size+1)
+	ids := r.Perm(n * 3)[:n]
+	goods := make([]GoodPriority, 0, n)
+	for _, id := range ids {
+		goods = append(goods, GoodPriority{
+			ID:       int64(id + 1),
+			Priority: int32(r.Intn(n*2) + 1),
+		})
+	}
+	return reflect.ValueOf(project{Goods: goods})
+}
+
+type moveCase struct {
+	Project     project
+	ID          int64
+	NewPriority int32
+}
+
+func (moveCase) Generate(r *rand.Rand, size int) reflect.Value {
+	p := project{}.Generate(r, size).Interface().(project)
+	n := len(p.Goods)
+	return reflect.ValueOf(moveCase{
+		Project:     p,
+		ID:          p.Goods[r.Intn(n)].ID,
+		NewPriority: int32(r.Intn(n+4) - 2),
+	})
+}
+
+type reorderCase struct {
+	Project project
+	IDs     []int64
+}
+
+func (reorderCase) Generate(r *rand.Rand, size int) reflect.Value {
+	p := project{}.Generate(r, size).Interface().(project)
+	ids := make([]int64, 0, len(p.Goods))
+	for _, i := range r.Perm(len(p.Goods))[:r.Intn(len(p.Goods)+1)] {
+		ids = append(ids, p.Goods[i].ID)
+	}
+	return reflect.ValueOf(reorderCase{
+		Project: p,
+		IDs:     ids,
+	})
+}
+
+func TestMoveGood(t *testing.T) {
+	property := func(c moveCase) bool {
+		goods := c.Project.Goods
+		changed, err := MoveGood(goods, c.ID, c.NewPriority)
+		if err != nil {
+			t.Logf("move good: %v", err)
+			return false
+		}
+		final, ok := apply(t, goods, changed)
+		if !ok || !dense(t, final, len(goods)) {
+			return false
+		}
+		want := c.NewPriority
+		if want < 1 {
+			want = 1
+		}
+		if want > int32(len(goods)) {
+			want = int32(len(goods))
+		}
+		if final[c.ID] != want {
+			t.Logf("moved good %d has priority %d, want %d", c.ID, final[c.ID], want)
+			return false
+		}
+		return sameOrder(t, without(order(goods), c.ID), without(finalOrder(final), c.ID))
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRemoveGood(t *testing.T) {
+	property := func(c moveCase) bool {
+		goods := c.Project.Goods
+		changed, err := RemoveGood(goods, c.ID)
+		if err != nil {
+			t.Logf("remove good: %v", err)
+			return false
+		}
+		remaining := make([]GoodPriority, 0, len(goods))
+		for _, good := range goods {
+			if good.ID != c.ID {
+				remaining = append(remaining, good)
+			}
+		}
+		final, ok := apply(t, remaining, changed)
+		if !ok || !dense(t, final, len(remaining)) {
+			return false
+		}
+		return sameOrder(t, without(order(goods), c.ID), finalOrder(final))
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestReorder(t *testing.T) {
+	property := func(c reorderCase) bool {
+		goods := c.Project.Goods
+		changed, err := Reorder(goods, c.IDs)
+		if err != nil {
+			t.Logf("reorder: %v", err)
+			return false
+		}
+		final, ok := apply(t, goods, changed)
+		if !ok || !dense(t, final, len(goods)) {
+			return false
+		}
+		listed := make(map[int64]bool, len(c.IDs))
+		for _, id := range c.IDs {
+			listed[id] = true
+		}
+		var listedOrder []int64
+		for i, id := range finalOrder(final) {
+			if listed[id] {
+				listedOrder = append(listedOrder, id)
+				continue
+			}
+			if before := order(goods)[i]; before != id {
+				t.Logf("unlisted good %d moved to position %d, held by %d before", id, i, before)
+				return false
+			}
+		}
+		return sameOrder(t, c.IDs, listedOrder)
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMoveGoodNotFound(t *testing.T) {
+	goods := []GoodPriority{{ID: 1, Priority: 1}}
+	if _, err := MoveGood(goods, 2, 1); err != ErrGoodNotFound {
+		t.Errorf("move good: got %v, want %v", err, ErrGoodNotFound)
+	}
+	if _, err := RemoveGood(goods, 2); err != ErrGoodNotFound {
+		t.Errorf("remove good: got %v, want %v", err, ErrGoodNotFound)
+	}
+	if _, err := Reorder(goods, []int64{2}); err != ErrGoodNotFound {
+		t.Errorf("reorder: got %v, want %v", err, ErrGoodNotFound)
+	}
+}
+
+// apply returns the priorities after the changes, making sure changed holds
+// exactly the goods whose priority differs, each once.
+func apply(t *testing.T, goods []GoodPriority, changed []GoodPriority) (final map[int64]int32, ok bool) {
+	final = make(map[int64]int32, len(goods))
+	for _, good := range goods {
+		final[good.ID] = good.Priority
+	}
+	seen := make(map[int64]bool, len(changed))
+	for _, good := range changed {
+		before, exists := final[good.ID]
+		if !exists || seen[good.ID] {
+			t.Logf("changed has unknown or duplicate good %d", good.ID)
+			return
+		}
+		if before == good.Priority {
+			t.Logf("changed has good %d with unchanged priority %d", good.ID, good.Priority)
+			return
+		}
+		seen[good.ID] = true
+		final[good.ID] = good.Priority
+	}
+	ok = true
+	return
+}
+
+// dense checks that the priorities are exactly 1..n. Once the order is
+// checked as well, the final priorities are the expected ones, so a good
+// missing from changed would have been caught.
+func dense(t *testing.T, final map[int64]int32, n int) bool {
+	seen := make(map[int32]bool, n)
+	for id, priority := range final {
+		if priority < 1 || priority > int32(n) || seen[priority] {
+			t.Logf("good %d has duplicate or out of range priority %d", id, priority)
+			return false
+		}
+		seen[priority] = true
+	}
+	return len(seen) == n
+}
+
+func order(goods []GoodPriority) (ids []int64) {
+	for _, good := range sortByPriority(goods) {
+		ids = append(ids, good.ID)
+	}
+	return
+}
+
+func finalOrder(final map[int64]int32) (ids []int64) {
+	ids = make([]int64, len(final))
+	for id, priority := range final {
+		ids[priority-1] = id
+	}
+	return
+}
+
+func without(ids []int64, id int64) (rest []int64) {
+	for _, other := range ids {
+		if other != id {
+			rest = append(rest, other)
+		}
+	}
+	return
+}
+
+func sameOrder(t *testing.T, want, got []int64) bool {
+	if len(want) != len(got) {
+		t.Logf("got %d goods, want %d", len(got), len(want))
+		return false
+	}
+	for i := range want {
+		if want[i] != got[i] {
+			t.Logf("got order %v, want %v", got, want)
+			return false
+		}
+	}
+	return true
+}
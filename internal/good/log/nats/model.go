@@ -3,11 +3,16 @@ package nats
 import "time"
 
 type nlog struct {
+	EventID     string    `json:"event_id"`
 	ID          int64     `json:"id"`
 	ProjectID   int64     `json:"projectId"`
 	Name        string    `json:"name"`
 	Description string    `json:"description"`
 	Priority    int32     `json:"priority"`
 	Removed     bool      `json:"removed"`
+	EventType   string    `json:"event_type"`
 	EventTime   time.Time `json:"event_at"`
+	Version     int64     `json:"version"`
+	// ChangedFields is only set for update events.
+	ChangedFields []string `json:"changed_fields,omitempty"`
 }
@@ -3,48 +3,152 @@ package nats
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/nats-io/nats.go"
 
 	"goods-service/internal/good/domain"
 )
 
-type Decoder interface {
-	DecodeLogs(ctx context.Context, data [][]byte)
-}
+const (
+	decodeErrorHeader     = "Goods-Decode-Error"
+	originalSubjectHeader = "Goods-Original-Subject"
+)
 
 type LogReader struct {
+	js           nats.JetStreamContext
 	subscription *nats.Subscription
 	batchSize    int32
+	maxWait      time.Duration
+	deadLetter   string
 }
 
-func NewLogReader(subscription *nats.Subscription, batchSize int32) *LogReader {
+func NewLogReader(js nats.JetStreamContext, subscription *nats.Subscription, batchSize int32, maxWait time.Duration,
+	deadLetter string) *LogReader {
 	return &LogReader{
+		js:           js,
 		subscription: subscription,
 		batchSize:    batchSize,
+		maxWait:      maxWait,
+		deadLetter:   deadLetter,
 	}
 }
 
-func (r *LogReader) FetchLogs(ctx context.Context) (logs []domain.Log, ackBatch func() (err error), err error) {
-	messages, err := r.subscription.Fetch(int(r.batchSize))
+// FetchLogs collects messages until batchSize of them arrived or maxWait
+// passed, whichever comes first, so that a busy stream yields large
+// inserts and a quiet one is still flushed in time. An empty batch with a
+// nil error is returned when nothing arrived. Messages that cannot be
+// decoded are moved to the dead-letter subject and acked, the rest of the
+// batch is returned as usual. Without a dead-letter subject they are only
+// acked, leaving dead-lettering to another consumer of the stream.
+func (r *LogReader) FetchLogs(ctx context.Context) (logs []domain.Log, ackBatch func() (err error),
+	nakBatch func(delay time.Duration) (err error), err error) {
+	messages, err := r.collect(ctx)
 	if err != nil {
-		err = fmt.Errorf("subscription fetch: %w", err)
+		err = fmt.Errorf("collect: %w", err)
 		return
 	}
-	nlogs := make([]nlog, len(messages))
-	for _, message := range messages {
+	valid := make([]*nats.Msg, 0, len(messages))
+	nlogs := make([]nlog, 0, len(messages))
+	for i, message := range messages {
 		var nlog nlog
-		err = json.Unmarshal(message.Data, nlog)
+		decodeErr := json.Unmarshal(message.Data, &nlog)
+		if decodeErr == nil {
+			valid = append(valid, message)
+			nlogs = append(nlogs, nlog)
+			continue
+		}
+		err = r.sendToDeadLetter(ctx, message, decodeErr)
 		if err != nil {
-			err = fmt.Errorf("json unmarshal: %w", err)
+			err = fmt.Errorf("send to dead letter: %w", err)
+			nakMessages(append(valid, messages[i:]...), 0)
 			return
 		}
-		nlogs = append(nlogs, nlog)
 	}
 	logs = toLogs(nlogs)
 	ackBatch = func() (err error) {
-		return messages[len(messages)-1].Ack()
+		for _, message := range valid {
+			err = message.Ack()
+			if err != nil {
+				err = fmt.Errorf("ack: %w", err)
+				return
+			}
+		}
+		return
+	}
+	nakBatch = func(delay time.Duration) (err error) {
+		return nakMessages(valid, delay)
+	}
+	return
+}
+
+// collect keeps fetching until the batch is full or maxWait is over. If
+// ctx is cancelled after some messages arrived, they are returned so the
+// caller can still flush them.
+func (r *LogReader) collect(ctx context.Context) (messages []*nats.Msg, err error) {
+	deadline := time.Now().Add(r.maxWait)
+	messages = make([]*nats.Msg, 0, r.batchSize)
+	for len(messages) < int(r.batchSize) {
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			return
+		}
+		fetchCtx, cancel := context.WithTimeout(ctx, wait)
+		var fetched []*nats.Msg
+		fetched, err = r.subscription.Fetch(int(r.batchSize)-len(messages), nats.Context(fetchCtx))
+		cancel()
+		if err != nil {
+			if errors.Is(err, nats.ErrTimeout) || errors.Is(err, context.DeadlineExceeded) ||
+				(ctx.Err() != nil && len(messages) > 0) {
+				err = nil
+				return
+			}
+			nakMessages(messages, 0)
+			messages = nil
+			err = fmt.Errorf("subscription fetch: %w", err)
+			return
+		}
+		messages = append(messages, fetched...)
+	}
+	return
+}
+
+func (r *LogReader) sendToDeadLetter(ctx context.Context, message *nats.Msg, decodeErr error) (err error) {
+	if r.deadLetter == "" {
+		err = message.Ack()
+		if err != nil {
+			err = fmt.Errorf("ack: %w", err)
+		}
+		return
+	}
+	deadLetter := nats.NewMsg(r.deadLetter)
+	deadLetter.Data = message.Data
+	deadLetter.Header.Set(decodeErrorHeader, decodeErr.Error())
+	deadLetter.Header.Set(originalSubjectHeader, message.Subject)
+	_, err = r.js.PublishMsg(deadLetter, nats.Context(ctx))
+	if err != nil {
+		err = fmt.Errorf("publish: %w", err)
+		return
+	}
+	err = message.Ack()
+	if err != nil {
+		err = fmt.Errorf("ack: %w", err)
+		return
+	}
+	return
+}
+
+// nakMessages returns the messages to the stream, to be redelivered no sooner
+// than after delay.
+func nakMessages(messages []*nats.Msg, delay time.Duration) (err error) {
+	for _, message := range messages {
+		err = message.NakWithDelay(delay)
+		if err != nil {
+			err = fmt.Errorf("nak: %w", err)
+			return
+		}
 	}
 	return
 }
@@ -53,12 +157,18 @@ func toLogs(nlogs []nlog) (logs []domain.Log) {
 	logs = make([]domain.Log, 0, len(nlogs))
 	for _, nlog := range nlogs {
 		logs = append(logs, domain.Log{
+			EventID:     nlog.EventID,
 			ID:          nlog.ID,
 			ProjectID:   nlog.ProjectID,
 			Name:        nlog.Name,
 			Description: nlog.Description,
 			Priority:    nlog.Priority,
+			Removed:     nlog.Removed,
+			EventType:   domain.EventType(nlog.EventType),
 			EventTime:   nlog.EventTime,
+			Version:     nlog.Version,
+
+			ChangedFields: nlog.ChangedFields,
 		})
 	}
 	return
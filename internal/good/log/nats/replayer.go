@@ -0,0 +1,85 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+)
+
+// DeadLetterReplayer moves messages from the dead-letter subject back to
+// the subject they were originally published to.
+type DeadLetterReplayer struct {
+	js           nats.JetStreamContext
+	subscription *nats.Subscription
+	batchSize    int32
+	fallback     string
+}
+
+func NewDeadLetterReplayer(js nats.JetStreamContext, subscription *nats.Subscription, batchSize int32,
+	fallback string) *DeadLetterReplayer {
+	return &DeadLetterReplayer{
+		js:           js,
+		subscription: subscription,
+		batchSize:    batchSize,
+		fallback:     fallback,
+	}
+}
+
+// Replay republishes the dead letters pending when it was called, so
+// messages that fail again and come back are left for the next run.
+// Messages without an original subject header go to fallback.
+func (r *DeadLetterReplayer) Replay(ctx context.Context) (replayed int, err error) {
+	info, err := r.subscription.ConsumerInfo()
+	if err != nil {
+		err = fmt.Errorf("consumer info: %w", err)
+		return
+	}
+	pending := int(info.NumPending) + info.NumAckPending
+	for replayed < pending {
+		batchSize := int(r.batchSize)
+		if left := pending - replayed; left < batchSize {
+			batchSize = left
+		}
+		var messages []*nats.Msg
+		messages, err = r.subscription.Fetch(batchSize, nats.Context(ctx))
+		if err != nil {
+			if errors.Is(err, nats.ErrTimeout) || errors.Is(err, context.DeadlineExceeded) {
+				err = nil
+			} else {
+				err = fmt.Errorf("subscription fetch: %w", err)
+			}
+			return
+		}
+		for _, message := range messages {
+			err = r.replay(ctx, message)
+			if err != nil {
+				err = fmt.Errorf("replay message: %w", err)
+				return
+			}
+			replayed++
+		}
+	}
+	return
+}
+
+func (r *DeadLetterReplayer) replay(ctx context.Context, message *nats.Msg) (err error) {
+	subject := message.Header.Get(originalSubjectHeader)
+	if subject == "" {
+		subject = r.fallback
+	}
+	msg := nats.NewMsg(subject)
+	msg.Data = message.Data
+	_, err = r.js.PublishMsg(msg, nats.Context(ctx))
+	if err != nil {
+		err = fmt.Errorf("publish: %w", err)
+		return
+	}
+	err = message.Ack()
+	if err != nil {
+		err = fmt.Errorf("ack: %w", err)
+		return
+	}
+	return
+}
@@ -11,12 +11,13 @@ import (
 )
 
 type LogWriter struct {
-	conn    *nats.Conn
+	js      nats.JetStreamContext
 	subject string
 }
 
-func NewLogWriter(subject string) *LogWriter {
+func NewLogWriter(js nats.JetStreamContext, subject string) *LogWriter {
 	return &LogWriter{
+		js:      js,
 		subject: subject,
 	}
 }
@@ -28,7 +29,12 @@ func (w *LogWriter) SendLog(ctx context.Context, log domain.Log) (err error) {
 		return err
 	}
 
-	err = w.conn.Publish(w.subject, jsonData)
+	// The stream drops messages whose id it has seen within its duplicates
+	// window, so an event relayed twice is stored once.
+	message := nats.NewMsg(w.subject)
+	message.Data = jsonData
+	message.Header.Set(nats.MsgIdHdr, log.EventID)
+	_, err = w.js.PublishMsg(message, nats.Context(ctx))
 	if err != nil {
 		err = fmt.Errorf("publish log: %w", err)
 		return
@@ -37,12 +43,16 @@ func (w *LogWriter) SendLog(ctx context.Context, log domain.Log) (err error) {
 }
 
 func toNLog(log domain.Log) (nlog nlog) {
+	nlog.EventID = log.EventID
 	nlog.ID = log.ID
 	nlog.ProjectID = log.ProjectID
 	nlog.Name = log.Name
 	nlog.Description = log.Description
 	nlog.Priority = log.Priority
 	nlog.Removed = log.Removed
+	nlog.EventType = string(log.EventType)
 	nlog.EventTime = log.EventTime
+	nlog.Version = log.Version
+	nlog.ChangedFields = log.ChangedFields
 	return
 }
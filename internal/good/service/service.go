@@ -2,29 +2,61 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"time"
+
+	"golang.org/x/exp/slog"
+	"golang.org/x/sync/singleflight"
 
 	"goods-service/internal/good/domain"
+
+	ls "goods-service/pkg/log/slog"
 )
 
+// GoodCache is only read through by the service. Entries are evicted by
+// the cache invalidator when the change events of a write arrive from the
+// event stream, so writes do not depend on the cache being available.
 type GoodCache interface {
-	SetGoodsList(ctx context.Context, goodsList domain.GoodsList) (err error)
-	GetGoodsList(ctx context.Context) (goodsList domain.GoodsList, err error)
-	DeleteGoodsList(ctx context.Context) (err error)
+	SetGoodsList(ctx context.Context, listGoods domain.ListGoods, version domain.CacheVersion,
+		goodsList domain.GoodsList) (err error)
+	GetGoodsList(ctx context.Context, listGoods domain.ListGoods) (goodsList domain.GoodsList,
+		version domain.CacheVersion, err error)
+	SetGood(ctx context.Context, good domain.Good, version domain.CacheVersion) (err error)
+	GetGood(ctx context.Context, getGood domain.GetGood) (good domain.Good, version domain.CacheVersion, err error)
 }
 
 type GoodStorage interface {
 	CreateGood(ctx context.Context, createGood domain.CreateGood) (good domain.Good, err error)
+	GetGood(ctx context.Context, getGood domain.GetGood) (good domain.Good, err error)
 	UpdateGood(ctx context.Context, updateGood domain.UpdateGood) (good domain.Good, err error)
-	DeleteGood(ctx context.Context, deleteGood domain.DeleteGood) (err error)
+	DeleteGood(ctx context.Context, deleteGood domain.DeleteGood) (version int64, err error)
+	RestoreGood(ctx context.Context, restoreGood domain.RestoreGood) (good domain.Good, err error)
+	PurgeGood(ctx context.Context, purgeGood domain.PurgeGood) (err error)
 	ListGoods(ctx context.Context, listGoods domain.ListGoods) (goodsList domain.GoodsList, err error)
 	ReprioritizeGood(ctx context.Context, reprioritizeGood domain.ReprioritizeGood) (
+		goodsPriorities []domain.GoodPriority, version int64, err error)
+	ReorderGoods(ctx context.Context, reorderGoods domain.ReorderGoods) (
 		goodsPriorities []domain.GoodPriority, err error)
+	ImportGoods(ctx context.Context, projectID int64, goods []domain.ImportGood) (
+		imported []domain.ImportedGood, err error)
 }
 
+type LogStorage interface {
+	ListLogs(ctx context.Context, listLogs domain.ListLogs) (logsList domain.LogsList, err error)
+}
+
+// sharedLoadTimeout bounds a storage read shared by concurrent cache misses,
+// since it no longer ends with the request that started it.
+const sharedLoadTimeout = 5 * time.Second
+
 type GoodsService struct {
-	cache   GoodCache
-	storage GoodStorage
+	cache      GoodCache
+	storage    GoodStorage
+	logStorage LogStorage
+	group      singleflight.Group
+	log        *slog.Logger
 }
 
 func (s *GoodsService) Create(ctx context.Context, createGood domain.CreateGood) (good domain.Good, err error) {
@@ -38,11 +70,47 @@ func (s *GoodsService) Create(ctx context.Context, createGood domain.CreateGood)
 		err = fmt.Errorf("create good: %w", err)
 		return
 	}
-	err = s.cache.DeleteGoodsList(ctx)
+	return
+}
+
+// Get serves a single good from the cache and reads through to the
+// storage on a miss, the same way List does.
+func (s *GoodsService) Get(ctx context.Context, getGood domain.GetGood) (good domain.Good, err error) {
+	err = validateGetGood(getGood)
+	if err != nil {
+		err = fmt.Errorf("validate get good: %w", err)
+		return
+	}
+	good, version, err := s.cache.GetGood(ctx, getGood)
+	if err == nil {
+		return
+	}
+	// The version is only known on a clean miss, the good read from the
+	// storage is not cached otherwise.
+	cacheable := errors.Is(err, domain.ErrCacheMiss)
+	if !cacheable {
+		s.log.Warn("failed to get good from cache", ls.Error(err))
+	}
+	key := fmt.Sprintf("good|%d|%d", getGood.ProjectID, getGood.ID)
+	result, err := s.shared(ctx, key, func(ctx context.Context) (result any, err error) {
+		good, err := s.storage.GetGood(ctx, getGood)
+		if err != nil {
+			return
+		}
+		if cacheable {
+			cacheErr := s.cache.SetGood(ctx, good, version)
+			if cacheErr != nil {
+				s.log.Warn("failed to set good to cache", ls.Error(cacheErr))
+			}
+		}
+		result = good
+		return
+	})
 	if err != nil {
-		err = fmt.Errorf("delete goods list: %w", err)
+		err = fmt.Errorf("get good: %w", err)
 		return
 	}
+	good = result.(domain.Good)
 	return
 }
 
@@ -57,76 +125,226 @@ func (s *GoodsService) Update(ctx context.Context, updateGood domain.UpdateGood)
 		err = fmt.Errorf("update good: %w", err)
 		return
 	}
-	err = s.cache.DeleteGoodsList(ctx)
-	if err != nil {
-		err = fmt.Errorf("delete goods list: %w", err)
-		return
-	}
 	return
 }
 
-func (s *GoodsService) Delete(ctx context.Context, deleteGood domain.DeleteGood) (err error) {
+func (s *GoodsService) Delete(ctx context.Context, deleteGood domain.DeleteGood) (version int64, err error) {
 	err = validateDeleteGood(deleteGood)
 	if err != nil {
 		err = fmt.Errorf("validate delete good: %w", err)
 		return
 	}
-	err = s.storage.DeleteGood(ctx, deleteGood)
+	version, err = s.storage.DeleteGood(ctx, deleteGood)
 	if err != nil {
 		err = fmt.Errorf("delete good: %w", err)
 		return
 	}
-	err = s.cache.DeleteGoodsList(ctx)
+	return
+}
+
+func (s *GoodsService) Restore(ctx context.Context, restoreGood domain.RestoreGood) (good domain.Good, err error) {
+	err = validateRestoreGood(restoreGood)
 	if err != nil {
-		err = fmt.Errorf("delete goods list: %w", err)
+		err = fmt.Errorf("validate restore good: %w", err)
+		return
+	}
+	good, err = s.storage.RestoreGood(ctx, restoreGood)
+	if err != nil {
+		err = fmt.Errorf("restore good: %w", err)
 		return
 	}
 	return
 }
 
+func (s *GoodsService) Purge(ctx context.Context, purgeGood domain.PurgeGood) (err error) {
+	err = validatePurgeGood(purgeGood)
+	if err != nil {
+		err = fmt.Errorf("validate purge good: %w", err)
+		return
+	}
+	err = s.storage.PurgeGood(ctx, purgeGood)
+	if err != nil {
+		err = fmt.Errorf("purge good: %w", err)
+		return
+	}
+	return
+}
+
+// List serves lists from the cache and reads through to the storage on a
+// miss. Concurrent misses for the same query share one storage read, which
+// is cached at the cache version seen before it, so that an invalidation
+// during the read is not lost. The cache is best effort: when it fails the
+// list is read from the storage and the failure is only logged.
 func (s *GoodsService) List(ctx context.Context, listGoods domain.ListGoods) (goodsList domain.GoodsList, err error) {
 	err = validateListGoods(listGoods)
 	if err != nil {
 		err = fmt.Errorf("validate list goods: %w", err)
 		return
 	}
-	goodsList, err = s.storage.ListGoods(ctx, listGoods)
-	if err != nil {
-		err = fmt.Errorf("list goods: %w", err)
+	goodsList, version, err := s.cache.GetGoodsList(ctx, listGoods)
+	if err == nil {
 		return
 	}
-	err = s.cache.SetGoodsList(ctx, goodsList)
+	cacheable := errors.Is(err, domain.ErrCacheMiss)
+	if !cacheable {
+		s.log.Warn("failed to get goods list from cache", ls.Error(err))
+	}
+	result, err := s.shared(ctx, "list|"+listGoods.CacheKey(), func(ctx context.Context) (result any, err error) {
+		goodsList, err := s.storage.ListGoods(ctx, listGoods)
+		if err != nil {
+			return
+		}
+		if cacheable {
+			cacheErr := s.cache.SetGoodsList(ctx, listGoods, version, goodsList)
+			if cacheErr != nil {
+				s.log.Warn("failed to set goods list to cache", ls.Error(cacheErr))
+			}
+		}
+		result = goodsList
+		return
+	})
 	if err != nil {
-		err = fmt.Errorf("delete good: %w", err)
+		err = fmt.Errorf("list goods: %w", err)
 		return
 	}
+	goodsList = result.(domain.GoodsList)
 	return
 }
 
 func (s *GoodsService) Reprioritize(ctx context.Context, reprioritizeGood domain.ReprioritizeGood) (
-	goodsPriorities []domain.GoodPriority, err error) {
+	goodsPriorities []domain.GoodPriority, version int64, err error) {
 	err = validateReprioritizeGood(reprioritizeGood)
 	if err != nil {
 		err = fmt.Errorf("validate reprioritize good: %w", err)
 		return
 	}
-	goodsPriorities, err = s.storage.ReprioritizeGood(ctx, reprioritizeGood)
+	goodsPriorities, version, err = s.storage.ReprioritizeGood(ctx, reprioritizeGood)
 	if err != nil {
 		err = fmt.Errorf("reprioritize good: %w", err)
 		return
 	}
-	err = s.cache.DeleteGoodsList(ctx)
+	return
+}
+
+func (s *GoodsService) Reorder(ctx context.Context, reorderGoods domain.ReorderGoods) (
+	goodsPriorities []domain.GoodPriority, err error) {
+	err = validateReorderGoods(reorderGoods)
+	if err != nil {
+		err = fmt.Errorf("validate reorder goods: %w", err)
+		return
+	}
+	goodsPriorities, err = s.storage.ReorderGoods(ctx, reorderGoods)
+	if err != nil {
+		err = fmt.Errorf("reorder goods: %w", err)
+		return
+	}
+	return
+}
+
+// Import checks every row with the rules of Create and imports the valid
+// ones. Invalid rows are reported instead of failing the import. If the
+// storage fails after some chunks were committed, the rows it did not get
+// to are reported as well, so that only they need to be sent again.
+func (s *GoodsService) Import(ctx context.Context, importGoods domain.ImportGoods) (report domain.ImportReport,
+	err error) {
+	err = validateImportGoods(importGoods)
 	if err != nil {
-		err = fmt.Errorf("delete goods list: %w", err)
+		err = fmt.Errorf("validate import goods: %w", err)
 		return
 	}
+	report.Errors = append(report.Errors, importGoods.Errors...)
+	valid := make([]domain.ImportGood, 0, len(importGoods.Goods))
+	for _, importGood := range importGoods.Goods {
+		rowErr := validateImportGood(importGoods.ProjectID, importGood)
+		if rowErr != nil {
+			report.Errors = append(report.Errors, domain.ImportError{
+				Row:     importGood.Row,
+				Message: rowErr.Error(),
+			})
+			continue
+		}
+		valid = append(valid, importGood)
+	}
+	report.Created, err = s.storage.ImportGoods(ctx, importGoods.ProjectID, valid)
+	if err != nil {
+		if len(report.Created) == 0 {
+			err = fmt.Errorf("import goods: %w", err)
+			return
+		}
+		s.log.Error("failed to import goods", ls.Error(err))
+		err = nil
+		for _, importGood := range valid[len(report.Created):] {
+			report.Errors = append(report.Errors, domain.ImportError{
+				Row:     importGood.Row,
+				Message: "not imported: internal error",
+			})
+		}
+	}
+	sort.Slice(report.Errors, func(i, j int) bool {
+		return report.Errors[i].Row < report.Errors[j].Row
+	})
 	return
 }
 
-func NewGoodService(cache GoodCache, storage GoodStorage) (service *GoodsService) {
+func (s *GoodsService) History(ctx context.Context, listLogs domain.ListLogs) (logsList domain.LogsList, err error) {
+	err = validateListLogs(listLogs)
+	if err != nil {
+		err = fmt.Errorf("validate list logs: %w", err)
+		return
+	}
+	logsList, err = s.logStorage.ListLogs(ctx, listLogs)
+	if err != nil {
+		err = fmt.Errorf("list logs: %w", err)
+		return
+	}
+	return
+}
+
+// shared runs load once for concurrent callers with the same key. The load
+// runs on a context detached from the caller that started it, so that the
+// caller going away does not fail the others. Every caller still stops
+// waiting once its own ctx is done.
+func (s *GoodsService) shared(ctx context.Context, key string,
+	load func(ctx context.Context) (result any, err error)) (result any, err error) {
+	results := s.group.DoChan(key, func() (result any, err error) {
+		loadCtx, cancel := context.WithTimeout(detachedContext{ctx}, sharedLoadTimeout)
+		defer cancel()
+		return load(loadCtx)
+	})
+	select {
+	case <-ctx.Done():
+		err = ctx.Err()
+	case r := <-results:
+		result, err = r.Val, r.Err
+	}
+	return
+}
+
+// detachedContext keeps the values of a context but not its deadline and
+// cancellation.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (deadline time.Time, ok bool) {
+	return
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func NewGoodService(cache GoodCache, storage GoodStorage, logStorage LogStorage, log *slog.Logger) (
+	service *GoodsService) {
 	service = &GoodsService{
-		cache:   cache,
-		storage: storage,
+		cache:      cache,
+		storage:    storage,
+		logStorage: logStorage,
+		log:        log,
 	}
 	return
 }
@@ -2,10 +2,21 @@ package service
 
 import (
 	"fmt"
+	"unicode/utf8"
 
 	"goods-service/internal/good/domain"
 )
 
+const (
+	maxHistoryLimit       = 1000
+	maxListLimit          = 1000
+	maxReorderGoods       = 10000
+	maxIdempotencyKeySize = 255
+	maxImportGoods        = 10000
+	maxNameLength         = 60
+	maxDescriptionLength  = 120
+)
+
 func validateCreateGood(createGood domain.CreateGood) (err error) {
 	if createGood.ProjectID < 0 {
 		err = fmt.Errorf("%w: negative project id", domain.ErrBadRequest)
@@ -15,6 +26,26 @@ func validateCreateGood(createGood domain.CreateGood) (err error) {
 		err = fmt.Errorf("%w: empty name", domain.ErrBadRequest)
 		return
 	}
+	if utf8.RuneCountInString(createGood.Name) > maxNameLength {
+		err = fmt.Errorf("%w: name is longer than %d characters", domain.ErrBadRequest, maxNameLength)
+		return
+	}
+	if len(createGood.IdempotencyKey) > maxIdempotencyKeySize {
+		err = fmt.Errorf("%w: idempotency key is longer than %d bytes", domain.ErrBadRequest, maxIdempotencyKeySize)
+		return
+	}
+	return
+}
+
+func validateGetGood(getGood domain.GetGood) (err error) {
+	if getGood.ID < 0 {
+		err = fmt.Errorf("%w: negative id", domain.ErrBadRequest)
+		return
+	}
+	if getGood.ProjectID < 0 {
+		err = fmt.Errorf("%w: negative project id", domain.ErrBadRequest)
+		return
+	}
 	return
 }
 
@@ -27,12 +58,17 @@ func validateUpdateGood(updateGood domain.UpdateGood) (err error) {
 		err = fmt.Errorf("%w: negative project id", domain.ErrBadRequest)
 		return
 	}
-	if updateGood.Name == "" {
+	if updateGood.Name.Set && (updateGood.Name.Null || updateGood.Name.Value == "") {
 		err = fmt.Errorf("%w: empty name", domain.ErrBadRequest)
 		return
 	}
-	if updateGood.Description == "" {
-		err = fmt.Errorf("%w: empty description", domain.ErrBadRequest)
+	if utf8.RuneCountInString(updateGood.Name.Value) > maxNameLength {
+		err = fmt.Errorf("%w: name is longer than %d characters", domain.ErrBadRequest, maxNameLength)
+		return
+	}
+	if utf8.RuneCountInString(updateGood.Description.Value) > maxDescriptionLength {
+		err = fmt.Errorf("%w: description is longer than %d characters", domain.ErrBadRequest,
+			maxDescriptionLength)
 		return
 	}
 	return
@@ -50,15 +86,63 @@ func validateDeleteGood(deleteGood domain.DeleteGood) (err error) {
 	return
 }
 
+func validateRestoreGood(restoreGood domain.RestoreGood) (err error) {
+	if restoreGood.ID < 0 {
+		err = fmt.Errorf("%w: negative id", domain.ErrBadRequest)
+		return
+	}
+	if restoreGood.ProjectID < 0 {
+		err = fmt.Errorf("%w: negative project id", domain.ErrBadRequest)
+		return
+	}
+	return
+}
+
+func validatePurgeGood(purgeGood domain.PurgeGood) (err error) {
+	if purgeGood.ID < 0 {
+		err = fmt.Errorf("%w: negative id", domain.ErrBadRequest)
+		return
+	}
+	if purgeGood.ProjectID < 0 {
+		err = fmt.Errorf("%w: negative project id", domain.ErrBadRequest)
+		return
+	}
+	return
+}
+
 func validateListGoods(listGoods domain.ListGoods) (err error) {
 	if listGoods.Limit < 0 {
 		err = fmt.Errorf("%w: negative limit", domain.ErrBadRequest)
 		return
 	}
+	if listGoods.Limit > maxListLimit {
+		err = fmt.Errorf("%w: invalid limit: must not exceed %d", domain.ErrBadRequest, maxListLimit)
+		return
+	}
 	if listGoods.Offset < 0 {
 		err = fmt.Errorf("%w: negative offset", domain.ErrBadRequest)
 		return
 	}
+	if listGoods.ProjectID < 0 {
+		err = fmt.Errorf("%w: negative project id", domain.ErrBadRequest)
+		return
+	}
+	switch listGoods.Sort {
+	case "", domain.SortByPriority, domain.SortByCreatedAt, domain.SortByName:
+	default:
+		err = fmt.Errorf("%w: invalid sort: %s", domain.ErrBadRequest, listGoods.Sort)
+		return
+	}
+	if listGoods.Cursor != "" {
+		if listGoods.Offset != 0 {
+			err = fmt.Errorf("%w: cursor and offset are mutually exclusive", domain.ErrBadRequest)
+			return
+		}
+		if listGoods.Sort != "" && listGoods.Sort != domain.SortByPriority {
+			err = fmt.Errorf("%w: cursor is only supported when sorting by priority", domain.ErrBadRequest)
+			return
+		}
+	}
 	return
 }
 
@@ -72,7 +156,90 @@ func validateReprioritizeGood(reprioritizeGood domain.ReprioritizeGood) (err err
 		return
 	}
 	if reprioritizeGood.NewPriority < 1 {
-		err = fmt.Errorf("%w: invalid new priority: must be greater than 1", domain.ErrBadRequest)
+		err = fmt.Errorf("%w: invalid new priority: must be positive", domain.ErrBadRequest)
+		return
+	}
+	return
+}
+
+func validateReorderGoods(reorderGoods domain.ReorderGoods) (err error) {
+	if reorderGoods.ProjectID < 0 {
+		err = fmt.Errorf("%w: negative project id", domain.ErrBadRequest)
+		return
+	}
+	if len(reorderGoods.IDs) == 0 {
+		err = fmt.Errorf("%w: empty ids", domain.ErrBadRequest)
+		return
+	}
+	if len(reorderGoods.IDs) > maxReorderGoods {
+		err = fmt.Errorf("%w: more than %d ids", domain.ErrBadRequest, maxReorderGoods)
+		return
+	}
+	seen := make(map[int64]struct{}, len(reorderGoods.IDs))
+	for _, id := range reorderGoods.IDs {
+		if _, ok := seen[id]; ok {
+			err = fmt.Errorf("%w: duplicate id %d", domain.ErrBadRequest, id)
+			return
+		}
+		seen[id] = struct{}{}
+	}
+	return
+}
+
+func validateImportGoods(importGoods domain.ImportGoods) (err error) {
+	if importGoods.ProjectID < 0 {
+		err = fmt.Errorf("%w: negative project id", domain.ErrBadRequest)
+		return
+	}
+	rows := len(importGoods.Goods) + len(importGoods.Errors)
+	if rows == 0 {
+		err = fmt.Errorf("%w: no rows", domain.ErrBadRequest)
+		return
+	}
+	if rows > maxImportGoods {
+		err = fmt.Errorf("%w: more than %d rows", domain.ErrBadRequest, maxImportGoods)
+		return
+	}
+	return
+}
+
+// validateImportGood applies the rules of Create to a row, along with the
+// length of the description, which Create does not take.
+func validateImportGood(projectID int64, importGood domain.ImportGood) (err error) {
+	err = validateCreateGood(domain.CreateGood{
+		ProjectID: projectID,
+		Name:      importGood.Name,
+	})
+	if err != nil {
+		return
+	}
+	if utf8.RuneCountInString(importGood.Description) > maxDescriptionLength {
+		err = fmt.Errorf("%w: description is longer than %d characters", domain.ErrBadRequest,
+			maxDescriptionLength)
+		return
+	}
+	return
+}
+
+func validateListLogs(listLogs domain.ListLogs) (err error) {
+	if listLogs.GoodID <= 0 && listLogs.ProjectID <= 0 {
+		err = fmt.Errorf("%w: either id or project id is required", domain.ErrBadRequest)
+		return
+	}
+	if listLogs.GoodID < 0 {
+		err = fmt.Errorf("%w: negative id", domain.ErrBadRequest)
+		return
+	}
+	if listLogs.ProjectID < 0 {
+		err = fmt.Errorf("%w: negative project id", domain.ErrBadRequest)
+		return
+	}
+	if listLogs.Limit < 1 || listLogs.Limit > maxHistoryLimit {
+		err = fmt.Errorf("%w: invalid limit: must be between 1 and %d", domain.ErrBadRequest, maxHistoryLimit)
+		return
+	}
+	if !listLogs.From.IsZero() && !listLogs.To.IsZero() && !listLogs.From.Before(listLogs.To) {
+		err = fmt.Errorf("%w: invalid time range: from must be before to", domain.ErrBadRequest)
 		return
 	}
 	return
@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"goods-service/internal/good/domain"
+)
+
+// replayCreateGood looks up the response stored for the idempotency key of
+// the request. Requests with the same key are serialized by an advisory
+// lock held until the end of tx, so a concurrent retry waits for the first
+// request and then replays its response.
+func (s *GoodStorage) replayCreateGood(ctx context.Context, tx pgx.Tx, createGood domain.CreateGood) (
+	good domain.Good, found bool, err error) {
+	const lockQuery = `SELECT pg_advisory_xact_lock(hashtext('idempotency'), hashtext($1::TEXT || ':' || $2));`
+	_, err = tx.Exec(ctx, lockQuery, createGood.ProjectID, createGood.IdempotencyKey)
+	if err != nil {
+		err = fmt.Errorf("advisory lock: %w", err)
+		return
+	}
+	const selectQuery = `SELECT request_hash, response FROM idempotency_keys
+WHERE project_id = $1 AND key = $2 AND created_at > $3;`
+	var (
+		hash     string
+		jsonData []byte
+	)
+	err = tx.QueryRow(ctx, selectQuery, createGood.ProjectID, createGood.IdempotencyKey,
+		time.Now().Add(-s.idempotencyWindow)).Scan(&hash, &jsonData)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = nil
+			return
+		}
+		err = fmt.Errorf("select key: %w", err)
+		return
+	}
+	if hash != requestHash(createGood) {
+		err = domain.ErrIdempotencyKeyReused
+		return
+	}
+	var p payload
+	err = json.Unmarshal(jsonData, &p)
+	if err != nil {
+		err = fmt.Errorf("json unmarshal: %w", err)
+		return
+	}
+	good = fromPayload(p)
+	found = true
+	return
+}
+
+// saveCreateGood stores the response for the idempotency key, replacing an
+// expired entry of the same key.
+func saveCreateGood(ctx context.Context, tx pgx.Tx, createGood domain.CreateGood, good domain.Good) (err error) {
+	jsonData, err := json.Marshal(toPayload(good))
+	if err != nil {
+		err = fmt.Errorf("json marshal: %w", err)
+		return
+	}
+	const query = `INSERT INTO idempotency_keys (project_id, key, request_hash, response) VALUES ($1, $2, $3, $4)
+ON CONFLICT (project_id, key) DO UPDATE
+SET request_hash = EXCLUDED.request_hash, response = EXCLUDED.response, created_at = now();`
+	_, err = tx.Exec(ctx, query, createGood.ProjectID, createGood.IdempotencyKey, requestHash(createGood),
+		string(jsonData))
+	if err != nil {
+		err = fmt.Errorf("insert key: %w", err)
+		return
+	}
+	return
+}
+
+// DeleteExpiredIdempotencyKeys deletes up to limit keys older than the
+// idempotency window.
+func (s *GoodStorage) DeleteExpiredIdempotencyKeys(ctx context.Context, limit int32) (deleted int64, err error) {
+	const query = `DELETE FROM idempotency_keys WHERE (project_id, key) IN (
+SELECT project_id, key FROM idempotency_keys WHERE created_at <= $1 ORDER BY created_at LIMIT $2
+FOR UPDATE SKIP LOCKED);`
+	tag, err := s.pool.Exec(ctx, query, time.Now().Add(-s.idempotencyWindow), limit)
+	if err != nil {
+		err = fmt.Errorf("delete keys: %w", err)
+		return
+	}
+	deleted = tag.RowsAffected()
+	return
+}
+
+func requestHash(createGood domain.CreateGood) (hash string) {
+	sum := sha256.Sum256([]byte(strconv.FormatInt(createGood.ProjectID, 10) + "|" + createGood.Name + "|" +
+		createGood.RequestHash))
+	hash = hex.EncodeToString(sum[:])
+	return
+}
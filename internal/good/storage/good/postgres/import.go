@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"goods-service/internal/good/domain"
+)
+
+var importColumns = []string{"id", "project_id", "name", "description"}
+
+// ImportGoods copies the goods into the project, importChunkSize goods per
+// transaction, or all of them in one if it is not positive. On error the
+// goods of the chunks committed before are returned, in input order.
+func (s *GoodStorage) ImportGoods(ctx context.Context, projectID int64, goods []domain.ImportGood) (
+	imported []domain.ImportedGood, err error) {
+	chunkSize := s.importChunkSize
+	if chunkSize <= 0 {
+		chunkSize = len(goods)
+	}
+	imported = make([]domain.ImportedGood, 0, len(goods))
+	for start := 0; start < len(goods); start += chunkSize {
+		end := start + chunkSize
+		if end > len(goods) {
+			end = len(goods)
+		}
+		var chunk []domain.ImportedGood
+		chunk, err = s.importChunk(ctx, projectID, goods[start:end])
+		if err != nil {
+			err = fmt.Errorf("import rows %d-%d: %w", goods[start].Row, goods[end-1].Row, err)
+			return
+		}
+		imported = append(imported, chunk...)
+	}
+	return
+}
+
+// importChunk inserts the goods with COPY and records their created events.
+// COPY does not return the generated ids, so they are taken from the
+// sequence first. Priorities are still set by the insert trigger, which
+// COPY fires for every row.
+func (s *GoodStorage) importChunk(ctx context.Context, projectID int64, goods []domain.ImportGood) (
+	imported []domain.ImportedGood, err error) {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		err = fmt.Errorf("begin tx: %w", err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback(ctx)
+			if rollbackErr != nil {
+				rollbackErr = fmt.Errorf("tx rollback: %w", rollbackErr)
+				err = errors.Join(err, rollbackErr)
+			}
+		}
+	}()
+	ids, err := nextGoodIDs(ctx, tx, len(goods))
+	if err != nil {
+		err = fmt.Errorf("next good ids: %w", err)
+		return
+	}
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{"goods"}, importColumns,
+		pgx.CopyFromSlice(len(goods), func(i int) (row []any, err error) {
+			var description *string
+			if goods[i].Description != "" {
+				description = &goods[i].Description
+			}
+			row = []any{ids[i], projectID, goods[i].Name, description}
+			return
+		}))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation {
+			err = domain.ErrProjectNotFound
+		}
+		err = fmt.Errorf("copy goods: %w", err)
+		return
+	}
+	const selectQuery = `SELECT id, project_id, name, COALESCE(description, ''), priority, removed, created_at, version FROM goods WHERE id = ANY($1) AND project_id = $2 ORDER BY priority;`
+	rows, err := tx.Query(ctx, selectQuery, ids, projectID)
+	if err != nil {
+		err = fmt.Errorf("select goods: %w", err)
+		return
+	}
+	created := make([]domain.Good, 0, len(goods))
+	for rows.Next() {
+		var good domain.Good
+		err = rows.Scan(&good.ID, &good.ProjectID, &good.Name, &good.Description, &good.Priority, &good.Removed,
+			&good.CreatedAt, &good.Version)
+		if err != nil {
+			rows.Close()
+			err = fmt.Errorf("rows scan: %w", err)
+			return
+		}
+		created = append(created, good)
+	}
+	if err = rows.Err(); err != nil {
+		err = fmt.Errorf("rows error: %w", err)
+		return
+	}
+	err = insertEvents(ctx, tx, domain.EventCreated, created...)
+	if err != nil {
+		err = fmt.Errorf("insert events: %w", err)
+		return
+	}
+	err = tx.Commit(ctx)
+	if err != nil {
+		err = fmt.Errorf("tx commit: %w", err)
+		return
+	}
+	imported = make([]domain.ImportedGood, 0, len(goods))
+	for i, good := range goods {
+		imported = append(imported, domain.ImportedGood{
+			Row: good.Row,
+			ID:  ids[i],
+		})
+	}
+	return
+}
+
+func nextGoodIDs(ctx context.Context, tx pgx.Tx, n int) (ids []int64, err error) {
+	const query = `SELECT nextval(pg_get_serial_sequence('goods', 'id')) FROM generate_series(1, $1);`
+	rows, err := tx.Query(ctx, query, n)
+	if err != nil {
+		err = fmt.Errorf("select nextval: %w", err)
+		return
+	}
+	defer rows.Close()
+	ids = make([]int64, 0, n)
+	for rows.Next() {
+		var id int64
+		err = rows.Scan(&id)
+		if err != nil {
+			err = fmt.Errorf("rows scan: %w", err)
+			return
+		}
+		ids = append(ids, id)
+	}
+	if err = rows.Err(); err != nil {
+		err = fmt.Errorf("rows error: %w", err)
+		return
+	}
+	return
+}
@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"goods-service/internal/good/domain"
+)
+
+// payload is the snapshot of a good stored with its outbox event, as it
+// was written by the transaction that emitted the event.
+type payload struct {
+	ID          int64     `json:"id"`
+	ProjectID   int64     `json:"projectId"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Priority    int32     `json:"priority"`
+	Removed     bool      `json:"removed"`
+	CreatedAt   time.Time `json:"createdAt"`
+	Version     int64     `json:"version"`
+	// ChangedFields lists the fields an update changed.
+	ChangedFields []string `json:"changedFields,omitempty"`
+}
+
+// insertEvents records an outbox event of the given type with a snapshot
+// of every good.
+func insertEvents(ctx context.Context, tx pgx.Tx, eventType domain.EventType, goods ...domain.Good) (err error) {
+	payloads := make([]payload, 0, len(goods))
+	for _, good := range goods {
+		payloads = append(payloads, toPayload(good))
+	}
+	err = insertPayloads(ctx, tx, eventType, payloads)
+	return
+}
+
+func insertPayloads(ctx context.Context, tx pgx.Tx, eventType domain.EventType, payloads []payload) (err error) {
+	if len(payloads) == 0 {
+		return
+	}
+	eventIDs := make([]string, 0, len(payloads))
+	ids := make([]int64, 0, len(payloads))
+	projectIDs := make([]int64, 0, len(payloads))
+	versions := make([]int64, 0, len(payloads))
+	data := make([]string, 0, len(payloads))
+	for _, p := range payloads {
+		var jsonData []byte
+		jsonData, err = json.Marshal(p)
+		if err != nil {
+			err = fmt.Errorf("json marshal: %w", err)
+			return
+		}
+		eventIDs = append(eventIDs, uuid.NewString())
+		ids = append(ids, p.ID)
+		projectIDs = append(projectIDs, p.ProjectID)
+		versions = append(versions, p.Version)
+		data = append(data, string(jsonData))
+	}
+	const query = `INSERT INTO outbox(event_id, event_type, good_id, project_id, version, payload)
+SELECT e.event_id, $1, e.good_id, e.project_id, e.version, e.payload::JSONB
+FROM unnest($2::TEXT[], $3::BIGINT[], $4::BIGINT[], $5::BIGINT[], $6::TEXT[])
+AS e(event_id, good_id, project_id, version, payload);`
+	_, err = tx.Exec(ctx, query, string(eventType), eventIDs, ids, projectIDs, versions, data)
+	if err != nil {
+		err = fmt.Errorf("insert events: %w", err)
+		return
+	}
+	return
+}
+
+func fromPayload(p payload) (good domain.Good) {
+	good = domain.Good{
+		ID:          p.ID,
+		ProjectID:   p.ProjectID,
+		Name:        p.Name,
+		Description: p.Description,
+		Priority:    p.Priority,
+		Removed:     p.Removed,
+		CreatedAt:   p.CreatedAt,
+		Version:     p.Version,
+	}
+	return
+}
+
+func toPayload(good domain.Good) (p payload) {
+	p = payload{
+		ID:          good.ID,
+		ProjectID:   good.ProjectID,
+		Name:        good.Name,
+		Description: good.Description,
+		Priority:    good.Priority,
+		Removed:     good.Removed,
+		CreatedAt:   good.CreatedAt,
+		Version:     good.Version,
+	}
+	return
+}
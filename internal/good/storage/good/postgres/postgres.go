@@ -2,28 +2,108 @@ package postgres
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
-	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"goods-service/internal/good/domain"
 )
 
+const foreignKeyViolation = "23503"
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// cursor points at the last good of a page ordered by (priority, id).
+type cursor struct {
+	Priority int32 `json:"p"`
+	ID       int64 `json:"id"`
+}
+
 type GoodStorage struct {
-	pool *pgxpool.Pool
+	pool              *pgxpool.Pool
+	idempotencyWindow time.Duration
+	importChunkSize   int
 }
 
 func (s *GoodStorage) CreateGood(ctx context.Context, createGood domain.CreateGood) (good domain.Good, err error) {
-	const query = `INSERT INTO goods (project_id, name) VALUES ($1, $2) RETURNING id, project_id, name, description, priority, removed, created_at;`
-	row := s.pool.QueryRow(ctx, query, createGood.ProjectID, createGood.Name)
-	err = row.Scan(&good.ID, &good.ProjectID, &good.Name, &good.Description, &good.Priority, &good.Removed, &good.CreatedAt)
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
+		err = fmt.Errorf("begin tx: %w", err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback(ctx)
+			if rollbackErr != nil {
+				rollbackErr = fmt.Errorf("tx rollback: %w", rollbackErr)
+				err = errors.Join(err, rollbackErr)
+			}
+		}
+	}()
+	if createGood.IdempotencyKey != "" {
+		var found bool
+		good, found, err = s.replayCreateGood(ctx, tx, createGood)
+		if err != nil {
+			err = fmt.Errorf("replay create good: %w", err)
+			return
+		}
+		if found {
+			err = tx.Commit(ctx)
+			if err != nil {
+				err = fmt.Errorf("tx commit: %w", err)
+			}
+			return
+		}
+	}
+	const query = `INSERT INTO goods (project_id, name) VALUES ($1, $2) RETURNING id, project_id, name, COALESCE(description, ''), priority, removed, created_at, version;`
+	row := tx.QueryRow(ctx, query, createGood.ProjectID, createGood.Name)
+	err = row.Scan(&good.ID, &good.ProjectID, &good.Name, &good.Description, &good.Priority, &good.Removed, &good.CreatedAt, &good.Version)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation {
+			err = domain.ErrProjectNotFound
+		}
 		err = fmt.Errorf("insert query: %w", err)
 		return
 	}
+	err = insertEvents(ctx, tx, domain.EventCreated, good)
+	if err != nil {
+		err = fmt.Errorf("insert event: %w", err)
+		return
+	}
+	if createGood.IdempotencyKey != "" {
+		err = saveCreateGood(ctx, tx, createGood, good)
+		if err != nil {
+			err = fmt.Errorf("save create good: %w", err)
+			return
+		}
+	}
+	err = tx.Commit(ctx)
+	if err != nil {
+		err = fmt.Errorf("tx commit: %w", err)
+		return
+	}
+	return
+}
+
+func (s *GoodStorage) GetGood(ctx context.Context, getGood domain.GetGood) (good domain.Good, err error) {
+	const query = `SELECT id, project_id, name, COALESCE(description, ''), priority, removed, created_at, version FROM goods WHERE id = $1 AND project_id = $2;`
+	row := s.pool.QueryRow(ctx, query, getGood.ID, getGood.ProjectID)
+	err = row.Scan(&good.ID, &good.ProjectID, &good.Name, &good.Description, &good.Priority, &good.Removed, &good.CreatedAt, &good.Version)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = domain.ErrGoodNotFound
+		}
+		err = fmt.Errorf("select query: %w", err)
+		return
+	}
 	return
 }
 
@@ -42,24 +122,179 @@ func (s *GoodStorage) UpdateGood(ctx context.Context, updateGood domain.UpdateGo
 			}
 		}
 	}()
-	const selectQuery = `SELECT TRUE FROM goods WHERE id = $1 AND project_id = $2 FOR UPDATE;`
-	var exists bool
-	err = tx.QueryRow(ctx, selectQuery, updateGood.ID, updateGood.ProjectID).Scan(&exists)
+	const selectQuery = `SELECT id, project_id, name, description, priority, removed, created_at, version FROM goods WHERE id = $1 AND project_id = $2 FOR UPDATE;`
+	var description *string
+	row := tx.QueryRow(ctx, selectQuery, updateGood.ID, updateGood.ProjectID)
+	err = row.Scan(&good.ID, &good.ProjectID, &good.Name, &description, &good.Priority, &good.Removed, &good.CreatedAt, &good.Version)
 	if err != nil {
-		err = fmt.Errorf("check existance: %w", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = domain.ErrGoodNotFound
+		}
+		err = fmt.Errorf("select good: %w", err)
 		return
 	}
-	const updateQuery = `UPDATE goods SET name = $1, description = $2 WHERE id = $3 AND project_id = $4 RETURNING id, project_id, name, description, priority, removed, created_at;`
-	row := tx.QueryRow(ctx, updateQuery, updateGood.Name, updateGood.Description, updateGood.ID, updateGood.ProjectID)
-	err = row.Scan(&good.ID, &good.ProjectID, &good.Name, &good.Description, &good.Priority, &good.Removed, &good.CreatedAt)
+	if description != nil {
+		good.Description = *description
+	}
+	if updateGood.Version != 0 && good.Version != updateGood.Version {
+		err = domain.ErrConflict
+		return
+	}
+	var (
+		sets    []string
+		args    []any
+		changed []string
+	)
+	if updateGood.Name.Set && updateGood.Name.Value != good.Name {
+		args = append(args, updateGood.Name.Value)
+		sets = append(sets, fmt.Sprintf("name = $%d", len(args)))
+		changed = append(changed, domain.FieldName)
+	}
+	if updateGood.Description.Set {
+		var value *string
+		if !updateGood.Description.Null {
+			value = &updateGood.Description.Value
+		}
+		if (value == nil) != (description == nil) || (value != nil && *value != *description) {
+			args = append(args, value)
+			sets = append(sets, fmt.Sprintf("description = $%d", len(args)))
+			changed = append(changed, domain.FieldDescription)
+		}
+	}
+	if len(changed) == 0 {
+		err = tx.Commit(ctx)
+		if err != nil {
+			err = fmt.Errorf("tx commit: %w", err)
+		}
+		return
+	}
+	// The row read above is locked until the end of tx, so the changed
+	// fields are exactly what this update changes.
+	args = append(args, updateGood.ID, updateGood.ProjectID)
+	updateQuery := `UPDATE goods SET ` + strings.Join(sets, ", ") + `, version = version + 1` +
+		fmt.Sprintf(` WHERE id = $%d AND project_id = $%d`, len(args)-1, len(args)) +
+		` RETURNING id, project_id, name, COALESCE(description, ''), priority, removed, created_at, version;`
+	row = tx.QueryRow(ctx, updateQuery, args...)
+	err = row.Scan(&good.ID, &good.ProjectID, &good.Name, &good.Description, &good.Priority, &good.Removed, &good.CreatedAt, &good.Version)
 	if err != nil {
 		err = fmt.Errorf("update good: %w", err)
 		return
 	}
+	p := toPayload(good)
+	p.ChangedFields = changed
+	err = insertPayloads(ctx, tx, domain.EventUpdated, []payload{p})
+	if err != nil {
+		err = fmt.Errorf("insert event: %w", err)
+		return
+	}
+	err = tx.Commit(ctx)
+	if err != nil {
+		err = fmt.Errorf("tx commit: %w", err)
+		return
+	}
+	return
+}
 
-	eventID := uuid.New()
-	const insertQuery = `INSERT INTO outbox(event_id, good_id, project_id) VALUES ($1, $2, $3);`
-	_, err = tx.Exec(ctx, insertQuery, eventID, updateGood.ID, updateGood.ProjectID)
+// DeleteGood marks the good removed and returns its version. Removing a
+// removed good changes nothing, so that its purge is not put off.
+func (s *GoodStorage) DeleteGood(ctx context.Context, deleteGood domain.DeleteGood) (version int64, err error) {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		err = fmt.Errorf("begin tx: %w", err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback(ctx)
+			if rollbackErr != nil {
+				rollbackErr = fmt.Errorf("tx rollback: %w", rollbackErr)
+				err = errors.Join(err, rollbackErr)
+			}
+		}
+	}()
+	const deleteQuery = `UPDATE goods SET removed = $1, removed_at = COALESCE(removed_at, now()), version = version + 1 WHERE id = $2 AND project_id = $3 AND NOT removed AND ($4 = 0 OR version = $4) RETURNING id, project_id, name, COALESCE(description, ''), priority, removed, created_at, version;`
+	var good domain.Good
+	row := tx.QueryRow(ctx, deleteQuery, true, deleteGood.ID, deleteGood.ProjectID, deleteGood.Version)
+	err = row.Scan(&good.ID, &good.ProjectID, &good.Name, &good.Description, &good.Priority, &good.Removed, &good.CreatedAt, &good.Version)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		// Either the good is missing or at another version, or it is
+		// already removed and there is nothing to do.
+		version, err = checkVersion(ctx, tx, deleteGood.ID, deleteGood.ProjectID, deleteGood.Version)
+		if err != nil {
+			err = fmt.Errorf("delete good: %w", err)
+			return
+		}
+	case err != nil:
+		err = fmt.Errorf("delete good: %w", err)
+		return
+	default:
+		version = good.Version
+		err = insertEvents(ctx, tx, domain.EventRemoved, good)
+		if err != nil {
+			err = fmt.Errorf("insert event: %w", err)
+			return
+		}
+	}
+	err = tx.Commit(ctx)
+	if err != nil {
+		err = fmt.Errorf("tx commit: %w", err)
+		return
+	}
+	return
+}
+
+// RestoreGood clears the removed flag and moves the good to the end of the
+// project's priority order, the goods it passes shift up by one.
+func (s *GoodStorage) RestoreGood(ctx context.Context, restoreGood domain.RestoreGood) (good domain.Good, err error) {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		err = fmt.Errorf("begin tx: %w", err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback(ctx)
+			if rollbackErr != nil {
+				rollbackErr = fmt.Errorf("tx rollback: %w", rollbackErr)
+				err = errors.Join(err, rollbackErr)
+			}
+		}
+	}()
+	current, err := lockProjectPriorities(ctx, tx, restoreGood.ProjectID)
+	if err != nil {
+		err = fmt.Errorf("lock project priorities: %w", err)
+		return
+	}
+	err = checkRemoved(ctx, tx, restoreGood.ID, restoreGood.ProjectID, restoreGood.Version)
+	if err != nil {
+		err = fmt.Errorf("check removed: %w", err)
+		return
+	}
+	changed, err := domain.MoveGood(current, restoreGood.ID, int32(len(current)))
+	if err != nil {
+		err = fmt.Errorf("move good: %w", err)
+		return
+	}
+	others := make([]domain.GoodPriority, 0, len(changed))
+	for _, goodPriority := range changed {
+		if goodPriority.ID != restoreGood.ID {
+			others = append(others, goodPriority)
+		}
+	}
+	_, err = updatePriorities(ctx, tx, restoreGood.ProjectID, others)
+	if err != nil {
+		err = fmt.Errorf("update priorities: %w", err)
+		return
+	}
+	const restoreQuery = `UPDATE goods SET removed = FALSE, removed_at = NULL, priority = $1, version = version + 1 WHERE id = $2 AND project_id = $3 RETURNING id, project_id, name, COALESCE(description, ''), priority, removed, created_at, version;`
+	row := tx.QueryRow(ctx, restoreQuery, len(current), restoreGood.ID, restoreGood.ProjectID)
+	err = row.Scan(&good.ID, &good.ProjectID, &good.Name, &good.Description, &good.Priority, &good.Removed, &good.CreatedAt, &good.Version)
+	if err != nil {
+		err = fmt.Errorf("restore good: %w", err)
+		return
+	}
+	err = insertEvents(ctx, tx, domain.EventRestored, good)
 	if err != nil {
 		err = fmt.Errorf("insert event: %w", err)
 		return
@@ -72,7 +307,9 @@ func (s *GoodStorage) UpdateGood(ctx context.Context, updateGood domain.UpdateGo
 	return
 }
 
-func (s *GoodStorage) DeleteGood(ctx context.Context, deleteGood domain.DeleteGood) (err error) {
+// PurgeGood deletes a removed good and closes the gap it leaves in the
+// priority order.
+func (s *GoodStorage) PurgeGood(ctx context.Context, purgeGood domain.PurgeGood) (err error) {
 	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
 		err = fmt.Errorf("begin tx: %w", err)
@@ -87,22 +324,38 @@ func (s *GoodStorage) DeleteGood(ctx context.Context, deleteGood domain.DeleteGo
 			}
 		}
 	}()
-	const existanceQuery = `SELECT TRUE FROM goods WHERE id = $1 AND project_id = $2 FOR UPDATE;`
-	var exists bool
-	err = tx.QueryRow(ctx, existanceQuery, deleteGood.ID, deleteGood.ProjectID).Scan(&exists)
+	current, err := lockProjectPriorities(ctx, tx, purgeGood.ProjectID)
+	if err != nil {
+		err = fmt.Errorf("lock project priorities: %w", err)
+		return
+	}
+	err = checkRemoved(ctx, tx, purgeGood.ID, purgeGood.ProjectID, purgeGood.Version)
 	if err != nil {
-		err = fmt.Errorf("check existance: %w", err)
+		err = fmt.Errorf("check removed: %w", err)
 		return
 	}
-	const deleteQuery = `UPDATE goods SET removed = $1 WHERE id = $2 AND project_id = $3;`
-	_, err = tx.Exec(ctx, deleteQuery, true, deleteGood.ID, deleteGood.ProjectID)
+	const deleteQuery = `DELETE FROM goods WHERE id = $1 AND project_id = $2 RETURNING id, project_id, name, COALESCE(description, ''), priority, removed, created_at, version;`
+	var good domain.Good
+	row := tx.QueryRow(ctx, deleteQuery, purgeGood.ID, purgeGood.ProjectID)
+	err = row.Scan(&good.ID, &good.ProjectID, &good.Name, &good.Description, &good.Priority, &good.Removed, &good.CreatedAt, &good.Version)
 	if err != nil {
 		err = fmt.Errorf("delete good: %w", err)
 		return
 	}
-	eventID := uuid.New()
-	const insertQuery = `INSERT INTO outbox(event_id, good_id, project_id) VALUES ($1, $2, $3);`
-	_, err = tx.Exec(ctx, insertQuery, eventID, deleteGood.ID, deleteGood.ProjectID)
+	// The row is gone, but the purge is still a change of the good and its
+	// event must follow the removed one.
+	good.Version++
+	changed, err := domain.RemoveGood(current, purgeGood.ID)
+	if err != nil {
+		err = fmt.Errorf("remove good: %w", err)
+		return
+	}
+	_, err = updatePriorities(ctx, tx, purgeGood.ProjectID, changed)
+	if err != nil {
+		err = fmt.Errorf("update priorities: %w", err)
+		return
+	}
+	err = insertEvents(ctx, tx, domain.EventPurged, good)
 	if err != nil {
 		err = fmt.Errorf("insert event: %w", err)
 		return
@@ -115,32 +368,111 @@ func (s *GoodStorage) DeleteGood(ctx context.Context, deleteGood domain.DeleteGo
 	return
 }
 
+// PurgeRemovedGoods purges up to limit goods removed before the given
+// time, each in its own transaction. Goods restored or changed meanwhile
+// are skipped.
+func (s *GoodStorage) PurgeRemovedGoods(ctx context.Context, before time.Time, limit int32) (purged int, err error) {
+	const query = `SELECT id, project_id, version FROM goods WHERE removed AND removed_at < $1 ORDER BY removed_at LIMIT $2;`
+	rows, err := s.pool.Query(ctx, query, before, limit)
+	if err != nil {
+		err = fmt.Errorf("select removed goods: %w", err)
+		return
+	}
+	purgeGoods := make([]domain.PurgeGood, 0, limit)
+	for rows.Next() {
+		var purgeGood domain.PurgeGood
+		err = rows.Scan(&purgeGood.ID, &purgeGood.ProjectID, &purgeGood.Version)
+		if err != nil {
+			rows.Close()
+			err = fmt.Errorf("rows scan: %w", err)
+			return
+		}
+		purgeGoods = append(purgeGoods, purgeGood)
+	}
+	if err = rows.Err(); err != nil {
+		err = fmt.Errorf("rows error: %w", err)
+		return
+	}
+	for _, purgeGood := range purgeGoods {
+		err = s.PurgeGood(ctx, purgeGood)
+		if err != nil {
+			if errors.Is(err, domain.ErrGoodNotRemoved) || errors.Is(err, domain.ErrConflict) ||
+				errors.Is(err, domain.ErrGoodNotFound) {
+				err = nil
+				continue
+			}
+			err = fmt.Errorf("purge good %d: %w", purgeGood.ID, err)
+			return
+		}
+		purged++
+	}
+	return
+}
+
+// ListGoods returns a page of goods either by offset or, when a cursor is
+// given, by keyset on (priority, id). When sorting by priority the page
+// carries a cursor for the next one, so offset callers can switch to
+// keyset pagination after the first page.
 func (s *GoodStorage) ListGoods(ctx context.Context, listGoods domain.ListGoods) (goodsList domain.GoodsList, err error) {
-	const selectQuery = `SELECT id, project_id, name, description, priority, removed, created_at FROM goods LIMIT $1 OFFSET $2;`
-	rows, err := s.pool.Query(ctx, selectQuery, listGoods.Limit, listGoods.Offset)
+	where, args := listGoodsFilter(listGoods)
+	meta := domain.Meta{
+		Limit:  listGoods.Limit,
+		Offset: listGoods.Offset,
+	}
+	countQuery := `SELECT COUNT(*), COUNT(*) FILTER (WHERE removed) FROM goods` + where + `;`
+	err = s.pool.QueryRow(ctx, countQuery, args...).Scan(&meta.Total, &meta.Removed)
+	if err != nil {
+		err = fmt.Errorf("count goods: %w", err)
+		return
+	}
+	if listGoods.Cursor != "" {
+		var c cursor
+		c, err = decodeCursor(listGoods.Cursor)
+		if err != nil {
+			err = fmt.Errorf("%w: invalid cursor", domain.ErrBadRequest)
+			return
+		}
+		args = append(args, c.Priority, c.ID)
+		keyset := fmt.Sprintf("(priority, id) > ($%d, $%d)", len(args)-1, len(args))
+		if where == "" {
+			where = ` WHERE ` + keyset
+		} else {
+			where += ` AND ` + keyset
+		}
+	}
+	selectQuery := `SELECT id, project_id, name, COALESCE(description, ''), priority, removed, created_at, version FROM goods` +
+		where + ` ORDER BY ` + listGoodsOrder(listGoods.Sort) +
+		fmt.Sprintf(` LIMIT $%d OFFSET $%d;`, len(args)+1, len(args)+2)
+	rows, err := s.pool.Query(ctx, selectQuery, append(args, listGoods.Limit+1, listGoods.Offset)...)
 	if err != nil {
 		err = fmt.Errorf("get goods list: %w", err)
 		return
 	}
 	defer rows.Close()
-	goods := make([]domain.Good, listGoods.Limit)
-	meta := domain.Meta{}
+	goods := make([]domain.Good, 0)
 	for rows.Next() {
 		var good domain.Good
-		err = rows.Scan(&good.ID, &good.ProjectID, &good.Name, &good.Description, &good.Priority, &good.Removed, &good.CreatedAt)
+		err = rows.Scan(&good.ID, &good.ProjectID, &good.Name, &good.Description, &good.Priority, &good.Removed, &good.CreatedAt, &good.Version)
 		if err != nil {
 			err = fmt.Errorf("rows scan: %w", err)
 			return
 		}
-		meta.Total++
-		if good.Removed {
-			meta.Removed++
-		}
+		goods = append(goods, good)
 	}
 	if err = rows.Err(); err != nil {
 		err = fmt.Errorf("rows error: %w", err)
 		return
 	}
+	if len(goods) > int(listGoods.Limit) {
+		goods = goods[:listGoods.Limit]
+		if len(goods) > 0 && (listGoods.Sort == "" || listGoods.Sort == domain.SortByPriority) {
+			last := goods[len(goods)-1]
+			meta.NextCursor = encodeCursor(cursor{
+				Priority: last.Priority,
+				ID:       last.ID,
+			})
+		}
+	}
 	goodsList = domain.GoodsList{
 		Meta:  meta,
 		Goods: goods,
@@ -148,7 +480,97 @@ func (s *GoodStorage) ListGoods(ctx context.Context, listGoods domain.ListGoods)
 	return
 }
 
+// listGoodsFilter builds the WHERE clause shared by the count and the page
+// queries, so that Meta describes the whole filtered set.
+func listGoodsFilter(listGoods domain.ListGoods) (where string, args []any) {
+	var conditions []string
+	if listGoods.ProjectID != 0 {
+		args = append(args, listGoods.ProjectID)
+		conditions = append(conditions, fmt.Sprintf("project_id = $%d", len(args)))
+	}
+	if listGoods.Removed != nil {
+		args = append(args, *listGoods.Removed)
+		conditions = append(conditions, fmt.Sprintf("removed = $%d", len(args)))
+	}
+	if listGoods.Name != "" {
+		args = append(args, "%"+likeEscaper.Replace(listGoods.Name)+"%")
+		conditions = append(conditions, fmt.Sprintf("name ILIKE $%d", len(args)))
+	}
+	if len(conditions) > 0 {
+		where = ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	return
+}
+
+func listGoodsOrder(sort domain.GoodsSort) (order string) {
+	switch sort {
+	case domain.SortByCreatedAt:
+		order = `created_at, id`
+	case domain.SortByName:
+		order = `name, id`
+	default:
+		order = `priority, id`
+	}
+	return
+}
+
+// ReprioritizeGood moves the good to the new priority within its project
+// and shifts the goods in between, keeping the project's priorities dense
+// and unique. The project is serialized with an advisory lock, the same
+// one the insert trigger takes. version is the moved good's version after
+// the move.
 func (s *GoodStorage) ReprioritizeGood(ctx context.Context, reprioritizeGood domain.ReprioritizeGood) (
+	goodsPriorities []domain.GoodPriority, version int64, err error) {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		err = fmt.Errorf("begin tx: %w", err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback(ctx)
+			if rollbackErr != nil {
+				rollbackErr = fmt.Errorf("tx rollback: %w", rollbackErr)
+				err = errors.Join(err, rollbackErr)
+			}
+		}
+	}()
+	current, err := lockProjectPriorities(ctx, tx, reprioritizeGood.ProjectID)
+	if err != nil {
+		err = fmt.Errorf("lock project priorities: %w", err)
+		return
+	}
+	version, err = checkVersion(ctx, tx, reprioritizeGood.ID, reprioritizeGood.ProjectID, reprioritizeGood.Version)
+	if err != nil {
+		err = fmt.Errorf("check version: %w", err)
+		return
+	}
+	goodsPriorities, err = domain.MoveGood(current, reprioritizeGood.ID, reprioritizeGood.NewPriority)
+	if err != nil {
+		err = fmt.Errorf("move good: %w", err)
+		return
+	}
+	changed, err := updatePriorities(ctx, tx, reprioritizeGood.ProjectID, goodsPriorities)
+	if err != nil {
+		err = fmt.Errorf("update priorities: %w", err)
+		return
+	}
+	for _, good := range changed {
+		if good.ID == reprioritizeGood.ID {
+			version = good.Version
+		}
+	}
+	err = tx.Commit(ctx)
+	if err != nil {
+		err = fmt.Errorf("tx commit: %w", err)
+		return
+	}
+	return
+}
+
+// ReorderGoods sets the priorities of the listed goods to follow the given
+// order in a single transaction.
+func (s *GoodStorage) ReorderGoods(ctx context.Context, reorderGoods domain.ReorderGoods) (
 	goodsPriorities []domain.GoodPriority, err error) {
 	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
@@ -164,19 +586,98 @@ func (s *GoodStorage) ReprioritizeGood(ctx context.Context, reprioritizeGood dom
 			}
 		}
 	}()
-	const existanceQuery = `SELECT TRUE FROM goods WHERE id = $1 AND project_id = $2 FOR UPDATE;`
-	var exists bool
-	err = tx.QueryRow(ctx, existanceQuery, reprioritizeGood.ID, reprioritizeGood.ProjectID).Scan(&exists)
+	current, err := lockProjectPriorities(ctx, tx, reorderGoods.ProjectID)
 	if err != nil {
-		err = fmt.Errorf("check existance: %w", err)
+		err = fmt.Errorf("lock project priorities: %w", err)
 		return
 	}
-	const reprioritizeQuery = `UPDATE goods SET priority = $1 WHERE id = $2 AND project_id = $3 RETURNING id, priority;`
-	rows, err := tx.Query(ctx, reprioritizeQuery, true, reprioritizeGood.ID, reprioritizeGood.ProjectID)
+	goodsPriorities, err = domain.Reorder(current, reorderGoods.IDs)
 	if err != nil {
-		err = fmt.Errorf("reprioritize good: %w", err)
+		err = fmt.Errorf("reorder: %w", err)
 		return
 	}
+	_, err = updatePriorities(ctx, tx, reorderGoods.ProjectID, goodsPriorities)
+	if err != nil {
+		err = fmt.Errorf("update priorities: %w", err)
+		return
+	}
+	err = tx.Commit(ctx)
+	if err != nil {
+		err = fmt.Errorf("tx commit: %w", err)
+		return
+	}
+	return
+}
+
+// checkVersion tells why a conditional write matched no row: the good does
+// not exist or its version is not the expected one. A zero version matches
+// any, so only a missing good fails it. The row stays locked until the end
+// of tx, so a check before an unconditional write holds until the write.
+// current is the version the good is at.
+func checkVersion(ctx context.Context, tx pgx.Tx, id, projectID, version int64) (current int64, err error) {
+	const query = `SELECT version FROM goods WHERE id = $1 AND project_id = $2 FOR UPDATE;`
+	err = tx.QueryRow(ctx, query, id, projectID).Scan(&current)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = domain.ErrGoodNotFound
+			return
+		}
+		err = fmt.Errorf("select version: %w", err)
+		return
+	}
+	if version != 0 && current != version {
+		err = domain.ErrConflict
+		return
+	}
+	return
+}
+
+// checkRemoved makes sure the good exists, is at the given version and is
+// removed. Like checkVersion, it keeps the row locked until the end of tx.
+func checkRemoved(ctx context.Context, tx pgx.Tx, id, projectID, version int64) (err error) {
+	const query = `SELECT removed, version FROM goods WHERE id = $1 AND project_id = $2 FOR UPDATE;`
+	var (
+		removed bool
+		current int64
+	)
+	err = tx.QueryRow(ctx, query, id, projectID).Scan(&removed, &current)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = domain.ErrGoodNotFound
+			return
+		}
+		err = fmt.Errorf("select good: %w", err)
+		return
+	}
+	if version != 0 && current != version {
+		err = domain.ErrConflict
+		return
+	}
+	if !removed {
+		err = domain.ErrGoodNotRemoved
+		return
+	}
+	return
+}
+
+// lockProjectPriorities takes the per-project advisory lock that every
+// priority change, including the insert trigger, holds, and returns the
+// current priorities. Rows are not locked, edits of other columns go on.
+func lockProjectPriorities(ctx context.Context, tx pgx.Tx, projectID int64) (
+	goodsPriorities []domain.GoodPriority, err error) {
+	const lockQuery = `SELECT pg_advisory_xact_lock($1);`
+	_, err = tx.Exec(ctx, lockQuery, projectID)
+	if err != nil {
+		err = fmt.Errorf("advisory lock: %w", err)
+		return
+	}
+	const selectQuery = `SELECT id, priority FROM goods WHERE project_id = $1 ORDER BY priority, id;`
+	rows, err := tx.Query(ctx, selectQuery, projectID)
+	if err != nil {
+		err = fmt.Errorf("select priorities: %w", err)
+		return
+	}
+	defer rows.Close()
 	goodsPriorities = make([]domain.GoodPriority, 0)
 	for rows.Next() {
 		var goodPriority domain.GoodPriority
@@ -191,24 +692,81 @@ func (s *GoodStorage) ReprioritizeGood(ctx context.Context, reprioritizeGood dom
 		err = fmt.Errorf("rows error: %w", err)
 		return
 	}
-	eventID := uuid.New()
-	const insertQuery = `INSERT INTO outbox(event_id, good_id, project_id) VALUES ($1, $2, $3);`
-	_, err = tx.Exec(ctx, insertQuery, eventID, reprioritizeGood.ID, reprioritizeGood.ProjectID)
+	return
+}
+
+// updatePriorities writes the new priorities and records an outbox event
+// for every good that changed. It returns the changed goods.
+func updatePriorities(ctx context.Context, tx pgx.Tx, projectID int64, goodsPriorities []domain.GoodPriority) (
+	goods []domain.Good, err error) {
+	if len(goodsPriorities) == 0 {
+		return
+	}
+	ids := make([]int64, 0, len(goodsPriorities))
+	priorities := make([]int32, 0, len(goodsPriorities))
+	for _, goodPriority := range goodsPriorities {
+		ids = append(ids, goodPriority.ID)
+		priorities = append(priorities, goodPriority.Priority)
+	}
+	const updateQuery = `UPDATE goods SET priority = c.priority, version = goods.version + 1
+FROM unnest($1::BIGINT[], $2::INT[]) AS c(id, priority)
+WHERE goods.id = c.id AND goods.project_id = $3
+RETURNING goods.id, goods.project_id, goods.name, COALESCE(goods.description, ''), goods.priority, goods.removed, goods.created_at, goods.version;`
+	rows, err := tx.Query(ctx, updateQuery, ids, priorities, projectID)
 	if err != nil {
-		err = fmt.Errorf("insert event: %w", err)
+		err = fmt.Errorf("update goods: %w", err)
 		return
 	}
-	err = tx.Commit(ctx)
+	goods = make([]domain.Good, 0, len(goodsPriorities))
+	for rows.Next() {
+		var good domain.Good
+		err = rows.Scan(&good.ID, &good.ProjectID, &good.Name, &good.Description, &good.Priority, &good.Removed,
+			&good.CreatedAt, &good.Version)
+		if err != nil {
+			rows.Close()
+			err = fmt.Errorf("rows scan: %w", err)
+			return
+		}
+		goods = append(goods, good)
+	}
+	if err = rows.Err(); err != nil {
+		err = fmt.Errorf("rows error: %w", err)
+		return
+	}
+	err = insertEvents(ctx, tx, domain.EventReprioritized, goods...)
 	if err != nil {
-		err = fmt.Errorf("tx commit: %w", err)
+		err = fmt.Errorf("insert events: %w", err)
+		return
+	}
+	return
+}
+
+func encodeCursor(c cursor) (s string) {
+	jsonData, _ := json.Marshal(c)
+	s = base64.RawURLEncoding.EncodeToString(jsonData)
+	return
+}
+
+func decodeCursor(s string) (c cursor, err error) {
+	jsonData, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		err = fmt.Errorf("base64 decode: %w", err)
+		return
+	}
+	err = json.Unmarshal(jsonData, &c)
+	if err != nil {
+		err = fmt.Errorf("json unmarshal: %w", err)
 		return
 	}
 	return
 }
 
-func NewGoodStorage(pool *pgxpool.Pool) (storage *GoodStorage) {
+func NewGoodStorage(pool *pgxpool.Pool, idempotencyWindow time.Duration, importChunkSize int) (
+	storage *GoodStorage) {
 	storage = &GoodStorage{
-		pool: pool,
+		pool:              pool,
+		idempotencyWindow: idempotencyWindow,
+		importChunkSize:   importChunkSize,
 	}
 	return
 }
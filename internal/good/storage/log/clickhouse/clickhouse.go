@@ -2,6 +2,11 @@ package clickhouse
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
 
 	"goods-service/internal/good/domain"
 
@@ -12,26 +17,157 @@ type LogStorage struct {
 	conn driver.Conn
 }
 
+// cursor points at the last log of a page, logs are ordered by
+// (EventTime, Id, EventId) descending. EventId breaks ties between events
+// of one good written at the same time.
+type cursor struct {
+	EventTime int64  `json:"t"`
+	ID        int64  `json:"id"`
+	EventID   string `json:"e"`
+}
+
 func NewLogStorage(conn driver.Conn) *LogStorage {
-	return &LogStorage{}
+	return &LogStorage{
+		conn: conn,
+	}
 }
 
 func (s *LogStorage) WriteLogs(ctx context.Context, logs []domain.Log) (err error) {
-	const query = `INSERT INTO logs`
+	const query = `INSERT INTO logs (EventId, Id, ProjectId, Name, Description, Priority, Removed, EventType, Version, ChangedFields,
+EventTime)`
 
 	batch, err := s.conn.PrepareBatch(ctx, query)
 	if err != nil {
+		err = fmt.Errorf("prepare batch: %w", err)
 		return
 	}
 
 	for _, log := range logs {
-		err := batch.Append(log.ID, log.ProjectID, log.Name, log.Description, log.Priority, log.Removed, log.EventTime)
+		err = batch.Append(log.EventID, uint64(log.ID), uint64(log.ProjectID), log.Name, log.Description,
+			uint32(log.Priority), log.Removed, string(log.EventType), uint64(log.Version), log.ChangedFields,
+			log.EventTime)
 		if err != nil {
-			return err
+			err = fmt.Errorf("batch append: %w", err)
+			return
 		}
 	}
 
-	batch.Send()
+	err = batch.Send()
+	if err != nil {
+		err = fmt.Errorf("batch send: %w", err)
+		return
+	}
 
 	return
 }
+
+func (s *LogStorage) ListLogs(ctx context.Context, listLogs domain.ListLogs) (logsList domain.LogsList, err error) {
+	var (
+		conditions []string
+		args       []any
+	)
+	if listLogs.GoodID != 0 {
+		conditions = append(conditions, "Id = ?")
+		args = append(args, uint64(listLogs.GoodID))
+	}
+	if listLogs.ProjectID != 0 {
+		conditions = append(conditions, "ProjectId = ?")
+		args = append(args, uint64(listLogs.ProjectID))
+	}
+	if !listLogs.From.IsZero() {
+		conditions = append(conditions, "EventTime >= ?")
+		args = append(args, listLogs.From)
+	}
+	if !listLogs.To.IsZero() {
+		conditions = append(conditions, "EventTime < ?")
+		args = append(args, listLogs.To)
+	}
+	if listLogs.Removed != nil {
+		conditions = append(conditions, "Removed = ?")
+		args = append(args, *listLogs.Removed)
+	}
+	if listLogs.Name != "" {
+		conditions = append(conditions, "positionCaseInsensitiveUTF8(Name, ?) > 0")
+		args = append(args, listLogs.Name)
+	}
+	if listLogs.Cursor != "" {
+		var c cursor
+		c, err = decodeCursor(listLogs.Cursor)
+		if err != nil {
+			err = fmt.Errorf("%w: invalid cursor", domain.ErrBadRequest)
+			return
+		}
+		conditions = append(conditions, "(EventTime, Id, EventId) < (?, ?, ?)")
+		args = append(args, time.Unix(0, c.EventTime).UTC(), uint64(c.ID), c.EventID)
+	}
+	query := `SELECT EventId, Id, ProjectId, Name, Description, Priority, Removed, EventType, Version, ChangedFields, EventTime
+FROM logs FINAL`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += ` ORDER BY EventTime DESC, Id DESC, EventId DESC LIMIT ?`
+	args = append(args, listLogs.Limit+1)
+
+	rows, err := s.conn.Query(ctx, query, args...)
+	if err != nil {
+		err = fmt.Errorf("select logs: %w", err)
+		return
+	}
+	defer rows.Close()
+	logs := make([]domain.Log, 0, listLogs.Limit+1)
+	for rows.Next() {
+		var (
+			id, projectID, version uint64
+			priority               uint32
+			eventType              string
+			log                    domain.Log
+		)
+		err = rows.Scan(&log.EventID, &id, &projectID, &log.Name, &log.Description, &priority, &log.Removed,
+			&eventType, &version, &log.ChangedFields, &log.EventTime)
+		if err != nil {
+			err = fmt.Errorf("rows scan: %w", err)
+			return
+		}
+		log.ID = int64(id)
+		log.ProjectID = int64(projectID)
+		log.Priority = int32(priority)
+		log.EventType = domain.EventType(eventType)
+		log.Version = int64(version)
+		logs = append(logs, log)
+	}
+	if err = rows.Err(); err != nil {
+		err = fmt.Errorf("rows error: %w", err)
+		return
+	}
+	if len(logs) > int(listLogs.Limit) {
+		logs = logs[:listLogs.Limit]
+		last := logs[len(logs)-1]
+		logsList.NextCursor = encodeCursor(cursor{
+			EventTime: last.EventTime.UnixNano(),
+			ID:        last.ID,
+			EventID:   last.EventID,
+		})
+	}
+	logsList.Logs = logs
+	return
+}
+
+func encodeCursor(c cursor) (s string) {
+	jsonData, _ := json.Marshal(c)
+	s = base64.RawURLEncoding.EncodeToString(jsonData)
+	return
+}
+
+func decodeCursor(s string) (c cursor, err error) {
+	jsonData, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		err = fmt.Errorf("base64 decode: %w", err)
+		return
+	}
+	err = json.Unmarshal(jsonData, &c)
+	if err != nil {
+		err = fmt.Errorf("json unmarshal: %w", err)
+		return
+	}
+	return
+}
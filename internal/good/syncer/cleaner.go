@@ -0,0 +1,143 @@
+package syncer
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"golang.org/x/exp/slog"
+
+	"goods-service/internal/good/domain"
+
+	ls "goods-service/pkg/log/slog"
+)
+
+// outboxVars is served by expvar at /debug/vars under the outbox key.
+var (
+	outboxVars      = expvar.NewMap("outbox")
+	backlogVar      = new(expvar.Int)
+	oldestUnsentVar = new(expvar.Float)
+)
+
+func init() {
+	outboxVars.Set("backlog", backlogVar)
+	outboxVars.Set("oldest_unsent_age_seconds", oldestUnsentVar)
+}
+
+type OutboxCleanerConfig struct {
+	Retention time.Duration
+	BatchSize int32
+	Interval  time.Duration
+}
+
+// OutboxCleaner deletes sent outbox events older than the retention and
+// keeps track of the unsent backlog. Rows are deleted in batches of
+// BatchSize, each in its own statement, so that no run holds row locks for
+// long.
+type OutboxCleaner struct {
+	pool   *pgxpool.Pool
+	config OutboxCleanerConfig
+	log    *slog.Logger
+
+	mu    sync.Mutex
+	stats domain.OutboxStats
+}
+
+func NewOutboxCleaner(pool *pgxpool.Pool, config OutboxCleanerConfig, log *slog.Logger) (cleaner *OutboxCleaner,
+	err error) {
+	if config.BatchSize <= 0 {
+		err = fmt.Errorf("invalid batch size %d: must be positive", config.BatchSize)
+		return
+	}
+	cleaner = &OutboxCleaner{
+		pool:   pool,
+		config: config,
+		log:    log,
+	}
+	return
+}
+
+// CleanOutbox cleans the outbox and refreshes the stats every interval
+// until ctx is cancelled.
+func (c *OutboxCleaner) CleanOutbox(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+		err := c.refreshStats(ctx)
+		if err != nil && ctx.Err() == nil {
+			c.log.Error("failed to collect outbox stats", ls.Error(err))
+		}
+		deleted, err := c.deleteSent(ctx)
+		if err != nil && ctx.Err() == nil {
+			c.log.Error("failed to clean outbox", ls.Error(err))
+		}
+		if deleted > 0 {
+			c.log.Debug("outbox cleaned", slog.Int64("deleted", deleted))
+		}
+		timer.Reset(c.config.Interval)
+	}
+}
+
+// OutboxStats returns the stats collected by the last run.
+func (c *OutboxCleaner) OutboxStats() (stats domain.OutboxStats) {
+	c.mu.Lock()
+	stats = c.stats
+	c.mu.Unlock()
+	return
+}
+
+func (c *OutboxCleaner) deleteSent(ctx context.Context) (deleted int64, err error) {
+	const query = `DELETE FROM outbox WHERE id IN (
+SELECT id FROM outbox WHERE sent = TRUE AND created_at < $1 ORDER BY created_at LIMIT $2 FOR UPDATE SKIP LOCKED);`
+	before := time.Now().Add(-c.config.Retention)
+	for {
+		var tag pgconn.CommandTag
+		tag, err = c.pool.Exec(ctx, query, before, c.config.BatchSize)
+		if err != nil {
+			err = fmt.Errorf("delete events: %w", err)
+			return
+		}
+		deleted += tag.RowsAffected()
+		outboxVars.Add("deleted", tag.RowsAffected())
+		if tag.RowsAffected() < int64(c.config.BatchSize) {
+			return
+		}
+	}
+}
+
+func (c *OutboxCleaner) refreshStats(ctx context.Context) (err error) {
+	const query = `SELECT COUNT(*), MIN(created_at) FROM outbox WHERE sent = FALSE;`
+	var (
+		backlog int64
+		oldest  *time.Time
+	)
+	err = c.pool.QueryRow(ctx, query).Scan(&backlog, &oldest)
+	if err != nil {
+		err = fmt.Errorf("select stats: %w", err)
+		return
+	}
+	stats := domain.OutboxStats{
+		Backlog:   backlog,
+		CheckedAt: time.Now(),
+	}
+	var oldestAge time.Duration
+	if oldest != nil {
+		stats.OldestUnsentAt = *oldest
+		oldestAge = stats.CheckedAt.Sub(*oldest)
+	}
+	c.mu.Lock()
+	c.stats = stats
+	c.mu.Unlock()
+	backlogVar.Set(stats.Backlog)
+	oldestUnsentVar.Set(oldestAge.Seconds())
+	return
+}
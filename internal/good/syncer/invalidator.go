@@ -0,0 +1,108 @@
+package syncer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang.org/x/exp/slog"
+
+	"goods-service/internal/good/domain"
+
+	ls "goods-service/pkg/log/slog"
+)
+
+type GoodCache interface {
+	DeleteGoodsList(ctx context.Context, projectID int64) (err error)
+	DeleteGoods(ctx context.Context, projectID int64, ids ...int64) (err error)
+}
+
+// CacheInvalidator evicts cached goods and lists of every project a change
+// event arrived for. A batch is acked only after the eviction succeeded,
+// otherwise it is nacked and redelivered, so the cache converges even if
+// the service crashed between the commit and the eviction.
+type CacheInvalidator struct {
+	reader      LogReader
+	cache       GoodCache
+	backoffBase time.Duration
+	backoffMax  time.Duration
+	log         *slog.Logger
+}
+
+func NewCacheInvalidator(reader LogReader, cache GoodCache, backoffBase, backoffMax time.Duration,
+	log *slog.Logger) (invalidator *CacheInvalidator) {
+	invalidator = &CacheInvalidator{
+		reader:      reader,
+		cache:       cache,
+		backoffBase: backoffBase,
+		backoffMax:  backoffMax,
+		log:         log,
+	}
+	return
+}
+
+func (i *CacheInvalidator) InvalidateCache(ctx context.Context) {
+	var attempt int
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+		logs, ackBatch, nakBatch, err := i.reader.FetchLogs(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			i.log.Error("failed to fetch events", ls.Error(err))
+			if !sleep(ctx, backoff(i.backoffBase, i.backoffMax, attempt)) {
+				return
+			}
+			attempt++
+			continue
+		}
+		if len(logs) == 0 {
+			attempt = 0
+			continue
+		}
+		err = i.evict(ctx, logs)
+		if err != nil {
+			i.log.Warn("failed to invalidate cache, returning batch to the stream", ls.Error(err))
+			delay := backoff(i.backoffBase, i.backoffMax, attempt)
+			err = nakBatch(delay)
+			if err != nil {
+				i.log.Error("failed to nak batch", ls.Error(err))
+			}
+			if !sleep(ctx, delay) {
+				return
+			}
+			attempt++
+			continue
+		}
+		attempt = 0
+		err = ackBatch()
+		if err != nil {
+			i.log.Error("failed to ack batch", ls.Error(err))
+		}
+	}
+}
+
+func (i *CacheInvalidator) evict(ctx context.Context, logs []domain.Log) (err error) {
+	projects := make(map[int64][]int64)
+	for _, log := range logs {
+		projects[log.ProjectID] = append(projects[log.ProjectID], log.ID)
+	}
+	for projectID, ids := range projects {
+		err = i.cache.DeleteGoods(ctx, projectID, ids...)
+		if err != nil {
+			err = fmt.Errorf("delete goods: %w", err)
+			return
+		}
+		err = i.cache.DeleteGoodsList(ctx, projectID)
+		if err != nil {
+			err = fmt.Errorf("delete goods list: %w", err)
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,78 @@
+package syncer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang.org/x/exp/slog"
+
+	ls "goods-service/pkg/log/slog"
+)
+
+type IdempotencyKeys interface {
+	DeleteExpiredIdempotencyKeys(ctx context.Context, limit int32) (deleted int64, err error)
+}
+
+type KeysCleanerConfig struct {
+	BatchSize int32
+	Interval  time.Duration
+}
+
+// KeysCleaner deletes idempotency keys past the idempotency window,
+// BatchSize keys at a time.
+type KeysCleaner struct {
+	keys   IdempotencyKeys
+	config KeysCleanerConfig
+	log    *slog.Logger
+}
+
+func NewKeysCleaner(keys IdempotencyKeys, config KeysCleanerConfig, log *slog.Logger) (cleaner *KeysCleaner,
+	err error) {
+	if config.BatchSize <= 0 {
+		err = fmt.Errorf("invalid batch size %d: must be positive", config.BatchSize)
+		return
+	}
+	cleaner = &KeysCleaner{
+		keys:   keys,
+		config: config,
+		log:    log,
+	}
+	return
+}
+
+// CleanKeys deletes expired keys every interval until ctx is cancelled.
+func (c *KeysCleaner) CleanKeys(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+		deleted, err := c.deleteExpired(ctx)
+		if err != nil && ctx.Err() == nil {
+			c.log.Error("failed to delete expired idempotency keys", ls.Error(err))
+		}
+		if deleted > 0 {
+			c.log.Debug("expired idempotency keys deleted", slog.Int64("deleted", deleted))
+		}
+		timer.Reset(c.config.Interval)
+	}
+}
+
+func (c *KeysCleaner) deleteExpired(ctx context.Context) (deleted int64, err error) {
+	for {
+		var n int64
+		n, err = c.keys.DeleteExpiredIdempotencyKeys(ctx, c.config.BatchSize)
+		deleted += n
+		if err != nil {
+			err = fmt.Errorf("delete expired idempotency keys: %w", err)
+			return
+		}
+		if n < int64(c.config.BatchSize) {
+			return
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package syncer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang.org/x/exp/slog"
+
+	ls "goods-service/pkg/log/slog"
+)
+
+type RemovedGoods interface {
+	PurgeRemovedGoods(ctx context.Context, before time.Time, limit int32) (purged int, err error)
+}
+
+type GoodsPurgerConfig struct {
+	Retention time.Duration
+	BatchSize int32
+	Interval  time.Duration
+}
+
+// GoodsPurger permanently deletes goods that have been removed for longer
+// than the retention, BatchSize goods at a time.
+type GoodsPurger struct {
+	goods  RemovedGoods
+	config GoodsPurgerConfig
+	log    *slog.Logger
+}
+
+func NewGoodsPurger(goods RemovedGoods, config GoodsPurgerConfig, log *slog.Logger) (purger *GoodsPurger,
+	err error) {
+	if config.BatchSize <= 0 {
+		err = fmt.Errorf("invalid batch size %d: must be positive", config.BatchSize)
+		return
+	}
+	purger = &GoodsPurger{
+		goods:  goods,
+		config: config,
+		log:    log,
+	}
+	return
+}
+
+// PurgeGoods purges removed goods every interval until ctx is cancelled.
+func (p *GoodsPurger) PurgeGoods(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+		purged, err := p.purge(ctx)
+		if err != nil && ctx.Err() == nil {
+			p.log.Error("failed to purge removed goods", ls.Error(err))
+		}
+		if purged > 0 {
+			p.log.Info("removed goods purged", slog.Int("purged", purged))
+		}
+		timer.Reset(p.config.Interval)
+	}
+}
+
+func (p *GoodsPurger) purge(ctx context.Context) (purged int, err error) {
+	before := time.Now().Add(-p.config.Retention)
+	for {
+		var n int
+		n, err = p.goods.PurgeRemovedGoods(ctx, before, p.config.BatchSize)
+		purged += n
+		if err != nil {
+			err = fmt.Errorf("purge removed goods: %w", err)
+			return
+		}
+		if n < int(p.config.BatchSize) {
+			return
+		}
+	}
+}
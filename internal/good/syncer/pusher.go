@@ -2,21 +2,191 @@ package syncer
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"golang.org/x/exp/slog"
 
 	"goods-service/internal/good/domain"
+
+	ls "goods-service/pkg/log/slog"
 )
 
+// payload mirrors the snapshot of a good the goods storage writes to the
+// outbox together with each event.
+type payload struct {
+	ID          int64  `json:"id"`
+	ProjectID   int64  `json:"projectId"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Priority    int32  `json:"priority"`
+	Removed     bool   `json:"removed"`
+	// ChangedFields is only set for update events.
+	ChangedFields []string `json:"changedFields"`
+}
+
 type LogWriter interface {
-	WriteLog(ctx context.Context, log domain.Log) (err error)
+	SendLog(ctx context.Context, log domain.Log) (err error)
 }
 
+// LogPusher relays events from the outbox table to the log writer in
+// outbox order. Only one pusher relays at a time: a batch is taken under a
+// transaction-level advisory lock and pushers that don't get it skip the
+// round, so events of one good are never published out of order.
 type LogPusher struct {
-	pool   *pgxpool.Pool
-	writer LogWriter
+	pool      *pgxpool.Pool
+	writer    LogWriter
+	batchSize int32
+	interval  time.Duration
+	log       *slog.Logger
+}
+
+func NewLogPusher(pool *pgxpool.Pool, writer LogWriter, batchSize int32, interval time.Duration,
+	log *slog.Logger) (pusher *LogPusher) {
+	pusher = &LogPusher{
+		pool:      pool,
+		writer:    writer,
+		batchSize: batchSize,
+		interval:  interval,
+		log:       log,
+	}
+	return
+}
+
+// PushLogs relays outbox batches until ctx is cancelled. A full batch is
+// followed by the next one right away, otherwise the pusher waits for the
+// configured interval.
+func (p *LogPusher) PushLogs(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+		pushed, err := p.pushBatch(ctx)
+		if err != nil && ctx.Err() == nil {
+			p.log.Error("failed to push logs", ls.Error(err))
+		}
+		if pushed == int(p.batchSize) {
+			timer.Reset(0)
+			continue
+		}
+		timer.Reset(p.interval)
+	}
 }
 
-func NewLogPusher() {}
+func (p *LogPusher) pushBatch(ctx context.Context) (pushed int, err error) {
+	tx, err := p.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		err = fmt.Errorf("begin tx: %w", err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback(ctx)
+			if rollbackErr != nil {
+				rollbackErr = fmt.Errorf("tx rollback: %w", rollbackErr)
+				err = errors.Join(err, rollbackErr)
+			}
+		}
+	}()
+	// The two-key form keeps this lock apart from the per-project ones.
+	const lockQuery = `SELECT pg_try_advisory_xact_lock(hashtext('outbox'), 0);`
+	var locked bool
+	err = tx.QueryRow(ctx, lockQuery).Scan(&locked)
+	if err != nil {
+		err = fmt.Errorf("advisory lock: %w", err)
+		return
+	}
+	if !locked {
+		err = tx.Commit(ctx)
+		if err != nil {
+			err = fmt.Errorf("tx commit: %w", err)
+		}
+		return
+	}
+	const selectQuery = `SELECT id, event_id, event_type, version, payload, created_at FROM outbox
+WHERE sent = FALSE ORDER BY id LIMIT $1 FOR UPDATE;`
+	rows, err := tx.Query(ctx, selectQuery, p.batchSize)
+	if err != nil {
+		err = fmt.Errorf("select events: %w", err)
+		return
+	}
+	ids := make([]int64, 0, p.batchSize)
+	logs := make([]domain.Log, 0, p.batchSize)
+	for rows.Next() {
+		var (
+			id        int64
+			eventID   string
+			eventType string
+			version   int64
+			jsonData  []byte
+			eventTime time.Time
+		)
+		err = rows.Scan(&id, &eventID, &eventType, &version, &jsonData, &eventTime)
+		if err != nil {
+			rows.Close()
+			err = fmt.Errorf("rows scan: %w", err)
+			return
+		}
+		var payload payload
+		err = json.Unmarshal(jsonData, &payload)
+		if err != nil {
+			rows.Close()
+			err = fmt.Errorf("json unmarshal event %s: %w", eventID, err)
+			return
+		}
+		ids = append(ids, id)
+		logs = append(logs, domain.Log{
+			ID:          payload.ID,
+			ProjectID:   payload.ProjectID,
+			Name:        payload.Name,
+			Description: payload.Description,
+			Priority:    payload.Priority,
+			Removed:     payload.Removed,
+			EventID:     eventID,
+			EventType:   domain.EventType(eventType),
+			EventTime:   eventTime.UTC(),
+			Version:     version,
 
-func (p *LogPusher) 
+			ChangedFields: payload.ChangedFields,
+		})
+	}
+	if err = rows.Err(); err != nil {
+		err = fmt.Errorf("rows error: %w", err)
+		return
+	}
+	if len(logs) == 0 {
+		err = tx.Commit(ctx)
+		if err != nil {
+			err = fmt.Errorf("tx commit: %w", err)
+		}
+		return
+	}
+	for _, log := range logs {
+		err = p.writer.SendLog(ctx, log)
+		if err != nil {
+			err = fmt.Errorf("send log: %w", err)
+			return
+		}
+	}
+	const updateQuery = `UPDATE outbox SET sent = TRUE WHERE id = ANY($1);`
+	_, err = tx.Exec(ctx, updateQuery, ids)
+	if err != nil {
+		err = fmt.Errorf("mark events sent: %w", err)
+		return
+	}
+	err = tx.Commit(ctx)
+	if err != nil {
+		err = fmt.Errorf("tx commit: %w", err)
+		return
+	}
+	pushed = len(logs)
+	return
+}
@@ -2,47 +2,163 @@ package syncer
 
 import (
 	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"golang.org/x/exp/slog"
 
 	"goods-service/internal/good/domain"
+
+	ls "goods-service/pkg/log/slog"
 )
 
 type LogReader interface {
-	ReadLogs(ctx context.Context) (logs []domain.Log, err error)
+	FetchLogs(ctx context.Context) (logs []domain.Log, ackBatch func() (err error),
+		nakBatch func(delay time.Duration) (err error), err error)
 }
 
 type LogStorage interface {
 	WriteLogs(ctx context.Context, logs []domain.Log) (err error)
 }
 
+type LogSyncerConfig struct {
+	FlushTimeout time.Duration
+	MaxAttempts  int
+	BackoffBase  time.Duration
+	BackoffMax   time.Duration
+}
+
+// LogSyncer moves logs from the reader to the storage. A batch is acked
+// only after the storage accepted it; failed writes are retried with
+// exponential backoff and the batch is nacked for redelivery once the
+// attempts are exhausted or the syncer is stopped. The redelivery of a
+// given up batch is delayed, longer with every batch given up in a row, so
+// an outage of the storage does not spin through the stream.
 type LogSyncer struct {
 	reader  LogReader
 	storage LogStorage
+	config  LogSyncerConfig
+	log     *slog.Logger
 }
 
-func NewLogSyncer(reader LogReader, storage LogStorage) *LogSyncer {
+func NewLogSyncer(reader LogReader, storage LogStorage, config LogSyncerConfig, log *slog.Logger) *LogSyncer {
+	if config.MaxAttempts < 1 {
+		config.MaxAttempts = 1
+	}
 	return &LogSyncer{
 		reader:  reader,
 		storage: storage,
+		config:  config,
+		log:     log,
 	}
 }
 
 func (s *LogSyncer) SyncLogs(ctx context.Context) {
+	var fetchAttempt, failures int
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		default:
-			logs, err := s.reader.ReadLogs(ctx)
-			if err != nil {
-				continue
+		}
+		logs, ackBatch, nakBatch, err := s.reader.FetchLogs(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
 			}
-			for {
-				err = s.storage.WriteLogs(ctx, logs)
-				if err != nil {
-					continue
-				}
-
+			s.log.Error("failed to fetch logs", ls.Error(err))
+			if !sleep(ctx, s.backoff(fetchAttempt)) {
+				return
 			}
+			fetchAttempt++
+			continue
+		}
+		fetchAttempt = 0
+		if len(logs) == 0 {
+			continue
+		}
+		if s.flush(ctx, logs, ackBatch, nakBatch, failures) {
+			failures = 0
+			continue
+		}
+		failures++
+	}
+}
+
+// flush writes the batch and reports whether it was written. failures is
+// the number of batches given up in a row before this one.
+func (s *LogSyncer) flush(ctx context.Context, logs []domain.Log, ackBatch func() (err error),
+	nakBatch func(delay time.Duration) (err error), failures int) (ok bool) {
+	for attempt := 0; attempt < s.config.MaxAttempts; attempt++ {
+		if attempt > 0 && !sleep(ctx, s.backoff(attempt-1)) {
+			s.log.Warn("syncer stopped before batch was written, returning it to the stream")
+			s.nak(nakBatch, 0)
+			return
+		}
+		err := s.write(logs)
+		if err != nil {
+			s.log.Warn("failed to write logs", slog.Int("attempt", attempt+1), ls.Error(err))
+			continue
+		}
+		err = ackBatch()
+		if err != nil {
+			s.log.Error("failed to ack batch", ls.Error(err))
 		}
+		ok = true
+		return
+	}
+	delay := s.backoff(failures)
+	s.log.Error("giving up on batch, returning it to the stream", slog.Int("size", len(logs)),
+		slog.Duration("redelivery_delay", delay))
+	s.nak(nakBatch, delay)
+	return
+}
+
+func (s *LogSyncer) write(logs []domain.Log) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.FlushTimeout)
+	defer cancel()
+	err = s.storage.WriteLogs(ctx, logs)
+	if err != nil {
+		err = fmt.Errorf("write logs: %w", err)
+		return
+	}
+	return
+}
+
+func (s *LogSyncer) nak(nakBatch func(delay time.Duration) (err error), delay time.Duration) {
+	err := nakBatch(delay)
+	if err != nil {
+		s.log.Error("failed to nak batch", ls.Error(err))
+	}
+}
+
+func (s *LogSyncer) backoff(attempt int) (delay time.Duration) {
+	delay = backoff(s.config.BackoffBase, s.config.BackoffMax, attempt)
+	return
+}
+
+// backoff returns an exponential delay for the given attempt with equal
+// jitter: half of the delay is fixed and the other half is random.
+func backoff(base, max time.Duration, attempt int) (delay time.Duration) {
+	delay = max
+	if attempt < 32 {
+		if d := base << attempt; d > 0 && d < delay {
+			delay = d
+		}
+	}
+	half := delay / 2
+	delay = half + time.Duration(rand.Int63n(int64(half)+1))
+	return
+}
+
+func sleep(ctx context.Context, d time.Duration) (ok bool) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
 	}
 }
@@ -0,0 +1,30 @@
+package v1
+
+import "time"
+
+type createProjectRequest struct {
+	Name string `json:"name"`
+}
+
+type renameProjectRequest struct {
+	Name string `json:"name"`
+}
+
+type meta struct {
+	Total    int32 `json:"total"`
+	Archived int32 `json:"archived"`
+	Limit    int32 `json:"limit"`
+	Offset   int32 `json:"offset"`
+}
+
+type projectResult struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Archived  bool      `json:"archived"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type projectsListResult struct {
+	Meta     meta            `json:"meta"`
+	Projects []projectResult `json:"projects"`
+}
@@ -0,0 +1,194 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+
+	"goods-service/internal/project/domain"
+)
+
+const (
+	projectIDParam = "id"
+	limitParam     = "limit"
+	offsetParam    = "offset"
+)
+
+type ProjectService interface {
+	Create(ctx context.Context, createProject domain.CreateProject) (project domain.Project, err error)
+	Rename(ctx context.Context, renameProject domain.RenameProject) (project domain.Project, err error)
+	Get(ctx context.Context, getProject domain.GetProject) (project domain.Project, err error)
+	Archive(ctx context.Context, archiveProject domain.ArchiveProject) (project domain.Project, err error)
+	List(ctx context.Context, listProjects domain.ListProjects) (projectsList domain.ProjectsList, err error)
+}
+
+type Controller struct {
+	service ProjectService
+}
+
+func (c *Controller) create(w http.ResponseWriter, r *http.Request) (err error) {
+	req := new(createProjectRequest)
+	err = render.DecodeJSON(r.Body, req)
+	if err != nil {
+		err = fmt.Errorf("%w: decode json: %v", domain.ErrBadRequest, err)
+		return
+	}
+	defer r.Body.Close()
+	project, err := c.service.Create(r.Context(), domain.CreateProject{
+		Name: req.Name,
+	})
+	if err != nil {
+		err = fmt.Errorf("service create: %w", err)
+		return
+	}
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, toProjectResult(project))
+	return
+}
+
+func (c *Controller) rename(w http.ResponseWriter, r *http.Request) (err error) {
+	projectID, err := getInt64Param(r, projectIDParam)
+	if err != nil {
+		err = fmt.Errorf("get url params: %w", err)
+		return
+	}
+	req := new(renameProjectRequest)
+	err = render.DecodeJSON(r.Body, req)
+	if err != nil {
+		err = fmt.Errorf("%w: decode json: %v", domain.ErrBadRequest, err)
+		return
+	}
+	defer r.Body.Close()
+	project, err := c.service.Rename(r.Context(), domain.RenameProject{
+		ID:   projectID,
+		Name: req.Name,
+	})
+	if err != nil {
+		err = fmt.Errorf("service rename: %w", err)
+		return
+	}
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, toProjectResult(project))
+	return
+}
+
+func (c *Controller) get(w http.ResponseWriter, r *http.Request) (err error) {
+	projectID, err := getInt64Param(r, projectIDParam)
+	if err != nil {
+		err = fmt.Errorf("get url params: %w", err)
+		return
+	}
+	project, err := c.service.Get(r.Context(), domain.GetProject{
+		ID: projectID,
+	})
+	if err != nil {
+		err = fmt.Errorf("service get: %w", err)
+		return
+	}
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, toProjectResult(project))
+	return
+}
+
+func (c *Controller) archive(w http.ResponseWriter, r *http.Request) (err error) {
+	projectID, err := getInt64Param(r, projectIDParam)
+	if err != nil {
+		err = fmt.Errorf("get url params: %w", err)
+		return
+	}
+	project, err := c.service.Archive(r.Context(), domain.ArchiveProject{
+		ID: projectID,
+	})
+	if err != nil {
+		err = fmt.Errorf("service archive: %w", err)
+		return
+	}
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, toProjectResult(project))
+	return
+}
+
+func (c *Controller) list(w http.ResponseWriter, r *http.Request) (err error) {
+	limit, err := getInt64Param(r, limitParam)
+	if err != nil {
+		err = fmt.Errorf("get url params: %w", err)
+		return
+	}
+	offset, err := getInt64Param(r, offsetParam)
+	if err != nil {
+		err = fmt.Errorf("get url params: %w", err)
+		return
+	}
+	if limit > math.MaxInt32 || offset > math.MaxInt32 || limit < math.MinInt32 || offset < math.MinInt32 {
+		err = fmt.Errorf("%w: limit or offset is out of range", domain.ErrBadRequest)
+		return
+	}
+	projectsList, err := c.service.List(r.Context(), domain.ListProjects{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		err = fmt.Errorf("service list: %w", err)
+		return
+	}
+	projectResults := make([]projectResult, 0, len(projectsList.Projects))
+	for _, project := range projectsList.Projects {
+		projectResults = append(projectResults, toProjectResult(project))
+	}
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, projectsListResult{
+		Meta: meta{
+			Total:    projectsList.Meta.Total,
+			Archived: projectsList.Meta.Archived,
+			Limit:    projectsList.Meta.Limit,
+			Offset:   projectsList.Meta.Offset,
+		},
+		Projects: projectResults,
+	})
+	return
+}
+
+func (c *Controller) Register(r chi.Router) {
+	eh := errorHandler{}
+	r.Post("/project/create", eh.wrap(c.create))
+	r.Patch("/project/rename", eh.wrap(c.rename))
+	r.Get("/project/get", eh.wrap(c.get))
+	r.Get("/project/list", eh.wrap(c.list))
+	r.Patch("/project/archive", eh.wrap(c.archive))
+}
+
+func NewController(service ProjectService) (controller *Controller) {
+	controller = &Controller{
+		service: service,
+	}
+	return
+}
+
+func toProjectResult(project domain.Project) (result projectResult) {
+	result = projectResult{
+		ID:        project.ID,
+		Name:      project.Name,
+		Archived:  project.Archived,
+		CreatedAt: project.CreatedAt,
+	}
+	return
+}
+
+func getInt64Param(r *http.Request, name string) (value int64, err error) {
+	str := r.URL.Query().Get(name)
+	if str == "" {
+		err = fmt.Errorf("%w: missing url param: %s", domain.ErrBadRequest, name)
+		return
+	}
+	value, err = strconv.ParseInt(str, 10, 64)
+	if err != nil {
+		err = fmt.Errorf("%w: %s has invalid syntax", domain.ErrBadRequest, name)
+		return
+	}
+	return
+}
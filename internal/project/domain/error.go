@@ -0,0 +1,8 @@
+package domain
+
+import "errors"
+
+var (
+	ErrProjectNotFound = errors.New("project not found")
+	ErrBadRequest      = errors.New("bad request")
+)
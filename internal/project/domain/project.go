@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+type Project struct {
+	ID        int64
+	Name      string
+	Archived  bool
+	CreatedAt time.Time
+}
+
+type CreateProject struct {
+	Name string
+}
+
+type RenameProject struct {
+	ID   int64
+	Name string
+}
+
+type GetProject struct {
+	ID int64
+}
+
+type ArchiveProject struct {
+	ID int64
+}
+
+type ListProjects struct {
+	Limit  int32
+	Offset int32
+}
+
+type Meta struct {
+	Total    int32
+	Archived int32
+	Limit    int32
+	Offset   int32
+}
+
+type ProjectsList struct {
+	Projects []Project
+	Meta     Meta
+}
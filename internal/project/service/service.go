@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"goods-service/internal/project/domain"
+)
+
+type ProjectStorage interface {
+	CreateProject(ctx context.Context, createProject domain.CreateProject) (project domain.Project, err error)
+	RenameProject(ctx context.Context, renameProject domain.RenameProject) (project domain.Project, err error)
+	GetProject(ctx context.Context, getProject domain.GetProject) (project domain.Project, err error)
+	ArchiveProject(ctx context.Context, archiveProject domain.ArchiveProject) (project domain.Project, err error)
+	ListProjects(ctx context.Context, listProjects domain.ListProjects) (
+		projectsList domain.ProjectsList, err error)
+}
+
+type ProjectsService struct {
+	storage ProjectStorage
+}
+
+func (s *ProjectsService) Create(ctx context.Context, createProject domain.CreateProject) (
+	project domain.Project, err error) {
+	err = validateCreateProject(createProject)
+	if err != nil {
+		err = fmt.Errorf("validate create project: %w", err)
+		return
+	}
+	project, err = s.storage.CreateProject(ctx, createProject)
+	if err != nil {
+		err = fmt.Errorf("create project: %w", err)
+		return
+	}
+	return
+}
+
+func (s *ProjectsService) Rename(ctx context.Context, renameProject domain.RenameProject) (
+	project domain.Project, err error) {
+	err = validateRenameProject(renameProject)
+	if err != nil {
+		err = fmt.Errorf("validate rename project: %w", err)
+		return
+	}
+	project, err = s.storage.RenameProject(ctx, renameProject)
+	if err != nil {
+		err = fmt.Errorf("rename project: %w", err)
+		return
+	}
+	return
+}
+
+func (s *ProjectsService) Get(ctx context.Context, getProject domain.GetProject) (project domain.Project, err error) {
+	err = validateGetProject(getProject)
+	if err != nil {
+		err = fmt.Errorf("validate get project: %w", err)
+		return
+	}
+	project, err = s.storage.GetProject(ctx, getProject)
+	if err != nil {
+		err = fmt.Errorf("get project: %w", err)
+		return
+	}
+	return
+}
+
+func (s *ProjectsService) Archive(ctx context.Context, archiveProject domain.ArchiveProject) (
+	project domain.Project, err error) {
+	err = validateArchiveProject(archiveProject)
+	if err != nil {
+		err = fmt.Errorf("validate archive project: %w", err)
+		return
+	}
+	project, err = s.storage.ArchiveProject(ctx, archiveProject)
+	if err != nil {
+		err = fmt.Errorf("archive project: %w", err)
+		return
+	}
+	return
+}
+
+func (s *ProjectsService) List(ctx context.Context, listProjects domain.ListProjects) (
+	projectsList domain.ProjectsList, err error) {
+	err = validateListProjects(listProjects)
+	if err != nil {
+		err = fmt.Errorf("validate list projects: %w", err)
+		return
+	}
+	projectsList, err = s.storage.ListProjects(ctx, listProjects)
+	if err != nil {
+		err = fmt.Errorf("list projects: %w", err)
+		return
+	}
+	return
+}
+
+func NewProjectService(storage ProjectStorage) (service *ProjectsService) {
+	service = &ProjectsService{
+		storage: storage,
+	}
+	return
+}
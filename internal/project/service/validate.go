@@ -0,0 +1,77 @@
+package service
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"goods-service/internal/project/domain"
+)
+
+const (
+	maxNameLength = 60
+	maxListLimit  = 1000
+)
+
+func validateCreateProject(createProject domain.CreateProject) (err error) {
+	err = validateName(createProject.Name)
+	if err != nil {
+		return
+	}
+	return
+}
+
+func validateRenameProject(renameProject domain.RenameProject) (err error) {
+	if renameProject.ID < 0 {
+		err = fmt.Errorf("%w: negative id", domain.ErrBadRequest)
+		return
+	}
+	err = validateName(renameProject.Name)
+	if err != nil {
+		return
+	}
+	return
+}
+
+func validateGetProject(getProject domain.GetProject) (err error) {
+	if getProject.ID < 0 {
+		err = fmt.Errorf("%w: negative id", domain.ErrBadRequest)
+		return
+	}
+	return
+}
+
+func validateArchiveProject(archiveProject domain.ArchiveProject) (err error) {
+	if archiveProject.ID < 0 {
+		err = fmt.Errorf("%w: negative id", domain.ErrBadRequest)
+		return
+	}
+	return
+}
+
+func validateListProjects(listProjects domain.ListProjects) (err error) {
+	if listProjects.Limit < 0 {
+		err = fmt.Errorf("%w: negative limit", domain.ErrBadRequest)
+		return
+	}
+	if listProjects.Limit > maxListLimit {
+		err = fmt.Errorf("%w: invalid limit: must not exceed %d", domain.ErrBadRequest, maxListLimit)
+		return
+	}
+	if listProjects.Offset < 0 {
+		err = fmt.Errorf("%w: negative offset", domain.ErrBadRequest)
+		return
+	}
+	return
+}
+
+func validateName(name string) (err error) {
+	if name == "" {
+		err = fmt.Errorf("%w: empty name", domain.ErrBadRequest)
+		return
+	}
+	if utf8.RuneCountInString(name) > maxNameLength {
+		err = fmt.Errorf("%w: name is longer than %d characters", domain.ErrBadRequest, maxNameLength)
+		return
+	}
+	return
+}
@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"goods-service/internal/project/domain"
+)
+
+type ProjectStorage struct {
+	pool *pgxpool.Pool
+}
+
+func (s *ProjectStorage) CreateProject(ctx context.Context, createProject domain.CreateProject) (
+	project domain.Project, err error) {
+	const query = `INSERT INTO projects (name) VALUES ($1) RETURNING id, name, archived, created_at;`
+	row := s.pool.QueryRow(ctx, query, createProject.Name)
+	err = row.Scan(&project.ID, &project.Name, &project.Archived, &project.CreatedAt)
+	if err != nil {
+		err = fmt.Errorf("insert query: %w", err)
+		return
+	}
+	return
+}
+
+func (s *ProjectStorage) RenameProject(ctx context.Context, renameProject domain.RenameProject) (
+	project domain.Project, err error) {
+	const query = `UPDATE projects SET name = $1 WHERE id = $2 RETURNING id, name, archived, created_at;`
+	row := s.pool.QueryRow(ctx, query, renameProject.Name, renameProject.ID)
+	err = row.Scan(&project.ID, &project.Name, &project.Archived, &project.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = domain.ErrProjectNotFound
+		}
+		err = fmt.Errorf("update query: %w", err)
+		return
+	}
+	return
+}
+
+func (s *ProjectStorage) GetProject(ctx context.Context, getProject domain.GetProject) (
+	project domain.Project, err error) {
+	const query = `SELECT id, name, archived, created_at FROM projects WHERE id = $1;`
+	row := s.pool.QueryRow(ctx, query, getProject.ID)
+	err = row.Scan(&project.ID, &project.Name, &project.Archived, &project.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = domain.ErrProjectNotFound
+		}
+		err = fmt.Errorf("select query: %w", err)
+		return
+	}
+	return
+}
+
+func (s *ProjectStorage) ArchiveProject(ctx context.Context, archiveProject domain.ArchiveProject) (
+	project domain.Project, err error) {
+	const query = `UPDATE projects SET archived = TRUE WHERE id = $1 RETURNING id, name, archived, created_at;`
+	row := s.pool.QueryRow(ctx, query, archiveProject.ID)
+	err = row.Scan(&project.ID, &project.Name, &project.Archived, &project.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = domain.ErrProjectNotFound
+		}
+		err = fmt.Errorf("update query: %w", err)
+		return
+	}
+	return
+}
+
+func (s *ProjectStorage) ListProjects(ctx context.Context, listProjects domain.ListProjects) (
+	projectsList domain.ProjectsList, err error) {
+	meta := domain.Meta{
+		Limit:  listProjects.Limit,
+		Offset: listProjects.Offset,
+	}
+	const countQuery = `SELECT COUNT(*), COUNT(*) FILTER (WHERE archived) FROM projects;`
+	err = s.pool.QueryRow(ctx, countQuery).Scan(&meta.Total, &meta.Archived)
+	if err != nil {
+		err = fmt.Errorf("count projects: %w", err)
+		return
+	}
+	const selectQuery = `SELECT id, name, archived, created_at FROM projects ORDER BY id LIMIT $1 OFFSET $2;`
+	rows, err := s.pool.Query(ctx, selectQuery, listProjects.Limit, listProjects.Offset)
+	if err != nil {
+		err = fmt.Errorf("get projects list: %w", err)
+		return
+	}
+	defer rows.Close()
+	projects := make([]domain.Project, 0)
+	for rows.Next() {
+		var project domain.Project
+		err = rows.Scan(&project.ID, &project.Name, &project.Archived, &project.CreatedAt)
+		if err != nil {
+			err = fmt.Errorf("rows scan: %w", err)
+			return
+		}
+		projects = append(projects, project)
+	}
+	if err = rows.Err(); err != nil {
+		err = fmt.Errorf("rows error: %w", err)
+		return
+	}
+	projectsList = domain.ProjectsList{
+		Meta:     meta,
+		Projects: projects,
+	}
+	return
+}
+
+func NewProjectStorage(pool *pgxpool.Pool) (storage *ProjectStorage) {
+	storage = &ProjectStorage{
+		pool: pool,
+	}
+	return
+}
@@ -0,0 +1,3 @@
+ALTER TABLE hezzl.logs DROP INDEX IF EXISTS idx_EventTime;
+
+ALTER TABLE hezzl.logs MODIFY COLUMN EventTime DateTime;
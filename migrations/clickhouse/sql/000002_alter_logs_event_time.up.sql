@@ -0,0 +1,3 @@
+ALTER TABLE hezzl.logs MODIFY COLUMN EventTime DateTime64(6, 'UTC');
+
+ALTER TABLE hezzl.logs ADD INDEX idx_EventTime(EventTime) TYPE minmax GRANULARITY 1;
@@ -0,0 +1 @@
+ALTER TABLE hezzl.logs DROP COLUMN IF EXISTS EventType;
@@ -0,0 +1 @@
+ALTER TABLE hezzl.logs ADD COLUMN IF NOT EXISTS EventType LowCardinality(String) DEFAULT '';
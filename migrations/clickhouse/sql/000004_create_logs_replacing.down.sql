@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS hezzl.logs_merge (
+    Id UInt64,
+    ProjectId UInt64,
+    Name String,
+    Description String,
+    Priority UInt32,
+    Removed Boolean DEFAULT false,
+    EventType LowCardinality(String) DEFAULT '',
+    EventTime DateTime64(6, 'UTC'),
+    INDEX idx_Id Id TYPE minmax GRANULARITY 1,
+    INDEX idx_ProjectId ProjectId TYPE minmax GRANULARITY 1,
+    INDEX idx_Name Name TYPE bloom_filter GRANULARITY 1,
+    INDEX idx_EventTime EventTime TYPE minmax GRANULARITY 1
+) ENGINE = MergeTree()
+ORDER BY (Id, ProjectId, Name);
+
+INSERT INTO hezzl.logs_merge (Id, ProjectId, Name, Description, Priority, Removed, EventType, EventTime)
+SELECT Id, ProjectId, Name, Description, Priority, Removed, EventType, EventTime
+FROM hezzl.logs FINAL;
+
+RENAME TABLE hezzl.logs TO hezzl.logs_replacing, hezzl.logs_merge TO hezzl.logs;
+
+DROP TABLE hezzl.logs_replacing;
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS hezzl.logs_replacing (
+    EventId String,
+    Id UInt64,
+    ProjectId UInt64,
+    Name String,
+    Description String,
+    Priority UInt32,
+    Removed Boolean DEFAULT false,
+    EventType LowCardinality(String) DEFAULT '',
+    Version UInt64 DEFAULT 0,
+    EventTime DateTime64(6, 'UTC'),
+    INDEX idx_ProjectId ProjectId TYPE minmax GRANULARITY 1,
+    INDEX idx_Name Name TYPE bloom_filter GRANULARITY 1,
+    INDEX idx_EventTime EventTime TYPE minmax GRANULARITY 1
+) ENGINE = ReplacingMergeTree(Version)
+ORDER BY (Id, EventId);
+
+INSERT INTO hezzl.logs_replacing (EventId, Id, ProjectId, Name, Description, Priority, Removed, EventType, EventTime)
+SELECT toString(generateUUIDv4()), Id, ProjectId, Name, Description, Priority, Removed, EventType, EventTime
+FROM hezzl.logs;
+
+RENAME TABLE hezzl.logs TO hezzl.logs_merge, hezzl.logs_replacing TO hezzl.logs;
+
+DROP TABLE hezzl.logs_merge;
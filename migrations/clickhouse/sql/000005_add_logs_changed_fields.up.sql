@@ -0,0 +1 @@
+ALTER TABLE hezzl.logs ADD COLUMN IF NOT EXISTS ChangedFields Array(LowCardinality(String)) AFTER Version;
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS outbox_unsent_idx ON outbox(id) WHERE sent = FALSE;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS goods_name_trgm_idx;
+
+DROP INDEX IF EXISTS goods_project_id_name_idx;
+
+DROP INDEX IF EXISTS goods_project_id_created_at_idx;
+
+DROP INDEX IF EXISTS goods_project_id_priority_idx;
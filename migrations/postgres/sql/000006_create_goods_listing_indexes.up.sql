@@ -0,0 +1,9 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS goods_project_id_priority_idx ON goods(project_id, priority, id);
+
+CREATE INDEX IF NOT EXISTS goods_project_id_created_at_idx ON goods(project_id, created_at, id);
+
+CREATE INDEX IF NOT EXISTS goods_project_id_name_idx ON goods(project_id, name, id);
+
+CREATE INDEX IF NOT EXISTS goods_name_trgm_idx ON goods USING GIN (name gin_trgm_ops);
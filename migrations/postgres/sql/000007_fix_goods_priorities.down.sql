@@ -0,0 +1,23 @@
+ALTER TABLE goods DROP CONSTRAINT IF EXISTS goods_project_id_priority_key;
+
+DROP TRIGGER IF EXISTS trigger_update_priorities ON goods;
+
+CREATE OR REPLACE FUNCTION update_priorities() RETURNS TRIGGER AS $$
+DECLARE
+    priority_diff INTEGER;
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        NEW.priority := COALESCE((SELECT MAX(priority) FROM goods), 0) + 1;
+    ELSIF TG_OP = 'UPDATE' AND NEW.priority <> OLD.priority THEN
+        UPDATE goods
+        SET priority = priority + priority_diff
+        WHERE priority >= NEW.priority AND id != NEW.id;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_update_priorities
+BEFORE INSERT OR UPDATE OF priority ON goods
+FOR EACH ROW
+EXECUTE FUNCTION update_priorities();
@@ -0,0 +1,25 @@
+DROP TRIGGER IF EXISTS trigger_update_priorities ON goods;
+
+CREATE OR REPLACE FUNCTION update_priorities() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_advisory_xact_lock(NEW.project_id);
+    NEW.priority := COALESCE((SELECT MAX(priority) FROM goods WHERE project_id = NEW.project_id), 0) + 1;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_update_priorities
+BEFORE INSERT ON goods
+FOR EACH ROW
+EXECUTE FUNCTION update_priorities();
+
+UPDATE goods g
+SET priority = r.priority
+FROM (
+    SELECT id, project_id, ROW_NUMBER() OVER (PARTITION BY project_id ORDER BY priority, id) AS priority
+    FROM goods
+) r
+WHERE g.id = r.id AND g.project_id = r.project_id AND g.priority <> r.priority;
+
+ALTER TABLE goods ADD CONSTRAINT goods_project_id_priority_key
+UNIQUE (project_id, priority) DEFERRABLE INITIALLY DEFERRED;
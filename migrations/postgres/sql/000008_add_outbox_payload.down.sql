@@ -0,0 +1,5 @@
+ALTER TABLE outbox DROP COLUMN IF EXISTS created_at;
+
+ALTER TABLE outbox DROP COLUMN IF EXISTS payload;
+
+ALTER TABLE outbox DROP COLUMN IF EXISTS event_type;
@@ -0,0 +1,25 @@
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS event_type TEXT NOT NULL DEFAULT 'updated';
+
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS payload JSONB;
+
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+-- Events written before the payload existed take the current state of the
+-- good, which is what the relay used to send for them anyway.
+UPDATE outbox o SET payload = jsonb_build_object(
+    'id', g.id,
+    'projectId', g.project_id,
+    'name', g.name,
+    'description', COALESCE(g.description, ''),
+    'priority', g.priority,
+    'removed', g.removed,
+    'createdAt', g.created_at AT TIME ZONE 'UTC'
+)
+FROM goods g
+WHERE g.id = o.good_id AND g.project_id = o.project_id AND o.payload IS NULL;
+
+DELETE FROM outbox WHERE payload IS NULL;
+
+ALTER TABLE outbox ALTER COLUMN payload SET NOT NULL;
+
+ALTER TABLE outbox ALTER COLUMN event_type DROP DEFAULT;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS outbox_sent_created_at_idx;
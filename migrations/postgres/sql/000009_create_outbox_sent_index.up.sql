@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS outbox_sent_created_at_idx ON outbox(created_at) WHERE sent = TRUE;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS outbox_event_id_idx;
+
+ALTER TABLE outbox DROP COLUMN IF EXISTS version;
+
+ALTER TABLE goods DROP COLUMN IF EXISTS version;
@@ -0,0 +1,5 @@
+ALTER TABLE goods ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 0;
+
+CREATE UNIQUE INDEX IF NOT EXISTS outbox_event_id_idx ON outbox(event_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS goods_removed_at_idx;
+
+ALTER TABLE goods DROP COLUMN IF EXISTS removed_at;
@@ -0,0 +1,5 @@
+ALTER TABLE goods ADD COLUMN IF NOT EXISTS removed_at TIMESTAMPTZ;
+
+UPDATE goods SET removed_at = now() WHERE removed AND removed_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS goods_removed_at_idx ON goods(removed_at) WHERE removed;
@@ -7,8 +7,22 @@ import (
 	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
 )
 
-func NewConnection(addr string) (conn driver.Conn, err error) {
-	options := &clickhouse.Options{Addr: []string{addr}}
+type Config struct {
+	Address  string
+	DB       string
+	User     string
+	Password string
+}
+
+func NewConnection(config *Config) (conn driver.Conn, err error) {
+	options := &clickhouse.Options{
+		Addr: []string{config.Address},
+		Auth: clickhouse.Auth{
+			Database: config.DB,
+			Username: config.User,
+			Password: config.Password,
+		},
+	}
 	conn, err = clickhouse.Open(options)
 	if err != nil {
 		err = fmt.Errorf("clickhouse open: %v", err)
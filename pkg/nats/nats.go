@@ -1,6 +1,7 @@
 package nats
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/nats-io/nats.go"
@@ -14,3 +15,30 @@ func NewConnection(url string) (conn *nats.Conn, err error) {
 	}
 	return
 }
+
+// NewJetStream returns a JetStream context and creates the stream
+// capturing subjects if it does not exist yet.
+func NewJetStream(conn *nats.Conn, stream string, subjects ...string) (js nats.JetStreamContext, err error) {
+	js, err = conn.JetStream()
+	if err != nil {
+		err = fmt.Errorf("jetstream: %w", err)
+		return
+	}
+	_, err = js.StreamInfo(stream)
+	if err == nil {
+		return
+	}
+	if !errors.Is(err, nats.ErrStreamNotFound) {
+		err = fmt.Errorf("stream info: %w", err)
+		return
+	}
+	_, err = js.AddStream(&nats.StreamConfig{
+		Name:     stream,
+		Subjects: subjects,
+	})
+	if err != nil {
+		err = fmt.Errorf("add stream: %w", err)
+		return
+	}
+	return
+}